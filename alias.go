@@ -0,0 +1,30 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Alias makes the binding of existing in the injector resolvable as alias
+// as well, e.g. under a legacy type during a refactor or under an
+// interface the bound type implements. The alias shares the value or the
+// provider of the binding, so that a memoized provider is invoked once for
+// both types, and keeps resolving to it even if existing is bound again.
+// Returns an error if existing is not bound in the injector itself or if
+// its values cannot be converted to alias.
+func (i *injector) Alias(existing, alias reflect.Type) error {
+	e, ok := i.snapshot().entry(existing)
+	if !ok {
+		return fmt.Errorf("Cannot alias type %v, it is not bound in the injector", existing)
+	}
+	if !existing.ConvertibleTo(alias) {
+		return fmt.Errorf("Cannot alias type %v as %v, it is not convertible", existing, alias)
+	}
+	if e.alias != nil {
+		existing = e.alias
+	}
+	a := *e
+	a.alias = existing
+	i.bind(alias, &a)
+	return nil
+}
@@ -0,0 +1,65 @@
+package inject_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type currentConfig struct {
+	Name string
+}
+
+type legacyConfig currentConfig
+
+func Test_InjectorAlias(t *testing.T) {
+	injector := inject.New()
+	calls := 0
+	injector.Provide(func() *currentConfig {
+		calls++
+		return &currentConfig{"app"}
+	})
+
+	current := reflect.TypeOf((*currentConfig)(nil))
+	legacy := reflect.TypeOf((*legacyConfig)(nil))
+	stringer := reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	expect(t, injector.Alias(current, legacy), nil)
+
+	_, err := injector.Invoke(func(c *currentConfig, l *legacyConfig) {
+		expect(t, l.Name, "app")
+		expect(t, (*currentConfig)(l), c)
+	})
+	expect(t, err, nil)
+	expect(t, calls, 1)
+
+	// the alias keeps the binding it was made from
+	injector.Map(&currentConfig{"replaced"})
+	expect(t, injector.Get(legacy).Interface().(*legacyConfig).Name, "app")
+
+	err = injector.Alias(reflect.TypeOf(""), legacy)
+	expect(t, err.Error(), "Cannot alias type string, it is not bound in the injector")
+	err = injector.Alias(current, stringer)
+	expect(t, err.Error(), "Cannot alias type *inject_test.currentConfig as fmt.Stringer, it is not convertible")
+}
+
+func Test_InjectorAliasInterface(t *testing.T) {
+	injector := inject.New()
+	injector.Map(greeter{"hi"})
+	stringer := reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	expect(t, injector.Alias(reflect.TypeOf(greeter{}), stringer), nil)
+
+	_, err := injector.Invoke(func(s fmt.Stringer) {
+		expect(t, s.String(), "hi")
+	})
+	expect(t, err, nil)
+}
+
+type greeter struct {
+	greeting string
+}
+
+func (g greeter) String() string {
+	return g.greeting
+}
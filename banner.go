@@ -0,0 +1,99 @@
+package inject
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StartupBanner returns an Option logging, once Warm or Validate is done,
+// a summary of the wiring of the injector to its logger, see WithLogger:
+// the profiles activated and the number of bindings of every scope of the
+// injector and its parents, and the lifecycle registered, e.g.
+//
+//	inject: profiles: cloud, prod
+//	inject: bindings: 12 in scope singleton (9 provided), 3 in scope request (1 provided)
+//	inject: lifecycle: 2 disposers, 4 Close, 1 OnDestroy, 1 rotated
+func StartupBanner() Option {
+	return func(i *injector) {
+		i.banner = true
+	}
+}
+
+// announce logs the summary of StartupBanner, if enabled.
+func (i *injector) announce() {
+	if !i.banner || i.logger == nil {
+		return
+	}
+
+	type scopeCount struct {
+		scope           Scope
+		bound, provided int
+	}
+	var scopes []*scopeCount
+	byScope := make(map[Scope]*scopeCount)
+	lifecycles := make(map[string]int)
+	i.forEach(0, func(b Binding, e *binding) bool {
+		s, ok := byScope[b.Scope]
+		if !ok {
+			s = &scopeCount{scope: b.Scope}
+			byScope[b.Scope] = s
+			scopes = append(scopes, s)
+		}
+		s.bound++
+		if e.provider != nil {
+			s.provided++
+			for _, l := range lifecycle(b.Type, e) {
+				lifecycles[l]++
+			}
+		}
+		return true
+	})
+
+	i.mu.Lock()
+	disposers := len(i.disposers)
+	i.mu.Unlock()
+	profiles := strings.Join(i.activated(), ", ")
+	if profiles == "" {
+		profiles = "none"
+	}
+	i.warnf("profiles: %s", profiles)
+
+	counts := make([]string, len(scopes))
+	for n, s := range scopes {
+		scope := "in scope " + string(s.scope)
+		if s.scope == "" {
+			scope = "unscoped"
+		}
+		counts[n] = fmt.Sprintf("%d %s (%d provided)", s.bound, scope, s.provided)
+	}
+	if len(counts) == 0 {
+		counts = []string{"none"}
+	}
+	i.warnf("bindings: %s", strings.Join(counts, ", "))
+
+	hooks := []string{fmt.Sprintf("%d disposers", disposers)}
+	names := make([]string, 0, len(lifecycles))
+	for l := range lifecycles {
+		names = append(names, l)
+	}
+	sort.Strings(names)
+	for _, l := range names {
+		hooks = append(hooks, fmt.Sprintf("%d %s", lifecycles[l], l))
+	}
+	i.warnf("lifecycle: %s", strings.Join(hooks, ", "))
+}
+
+// activated returns the profiles activated in the parents of the injector,
+// depth first, and then in the injector.
+func (i *injector) activated() []string {
+	var names []string
+	for _, p := range i.snapshot().parents {
+		if pi, ok := p.inj.(*injector); ok {
+			names = append(names, pi.activated()...)
+		}
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append(names, i.active...)
+}
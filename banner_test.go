@@ -0,0 +1,47 @@
+package inject_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type bannerDB struct{}
+
+func (*bannerDB) Close() error { return nil }
+
+func Test_StartupBanner(t *testing.T) {
+	var buf bytes.Buffer
+	app := inject.New(inject.WithLogger(log.New(&buf, "", 0)), inject.StartupBanner())
+	app.SetScope(inject.Singleton)
+	app.Profile("prod", func(m inject.TypeMapper) { m.Map("prod") })
+	expect(t, app.Activate("prod"), nil)
+	app.Provide(func() *bannerDB { return &bannerDB{} }, inject.OnDestroy(func(interface{}) error { return nil }))
+
+	req := app.Child(inject.StartupBanner())
+	req.SetScope(inject.Request)
+	req.Provide(func() int { return 1 }, inject.Transient())
+	req.OnDispose(func() error { return nil })
+
+	expect(t, req.Validate(), nil)
+	expect(t, buf.String(), "inject: profiles: prod\n"+
+		"inject: bindings: 1 in scope request (1 provided), 2 in scope singleton (1 provided)\n"+
+		"inject: lifecycle: 1 disposers, 1 Close, 1 OnDestroy, 1 transient\n")
+
+	buf.Reset()
+	expect(t, app.(inject.Warmer).Warm(context.Background()), nil)
+	expect(t, buf.String(), "inject: profiles: prod\n"+
+		"inject: bindings: 2 in scope singleton (1 provided)\n"+
+		"inject: lifecycle: 2 disposers, 1 Close, 1 OnDestroy\n")
+
+	buf.Reset()
+	expect(t, inject.New(inject.WithLogger(log.New(&buf, "", 0))).Validate(), nil)
+	expect(t, buf.String(), "")
+
+	buf.Reset()
+	expect(t, inject.New(inject.WithLogger(log.New(&buf, "", 0)), inject.StartupBanner()).Validate(), nil)
+	expect(t, buf.String(), "inject: profiles: none\ninject: bindings: none\ninject: lifecycle: 0 disposers\n")
+}
@@ -0,0 +1,56 @@
+package inject
+
+import (
+	"reflect"
+)
+
+// bindLeading resolves the longest prefix of the arguments of f that inj
+// can resolve and returns a function of the remaining arguments calling f
+// with the resolved prefix. The variadic argument of f is never resolved.
+// Returns an error if resolving an argument fails for another reason than
+// the type not being mapped.
+func bindLeading(inj Injector, f interface{}) (reflect.Value, error) {
+	fv := reflect.ValueOf(f)
+	t := fv.Type()
+
+	i, _ := inj.(*injector)
+	var bound []reflect.Value
+	for n := 0; n < t.NumIn(); n++ {
+		if t.IsVariadic() && n == t.NumIn()-1 {
+			break
+		}
+
+		var val reflect.Value
+		var err error
+		if i != nil {
+			val, err = i.resolve(t.In(n))
+		} else if val = inj.Get(t.In(n)); !val.IsValid() {
+			err = ErrNotFound
+		}
+		if missing(err) {
+			break
+		}
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		bound = append(bound, val)
+	}
+
+	ins := make([]reflect.Type, 0, t.NumIn()-len(bound))
+	for n := len(bound); n < t.NumIn(); n++ {
+		ins = append(ins, t.In(n))
+	}
+	outs := make([]reflect.Type, t.NumOut())
+	for n := range outs {
+		outs[n] = t.Out(n)
+	}
+
+	ft := reflect.FuncOf(ins, outs, t.IsVariadic())
+	return reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+		in := append(append([]reflect.Value(nil), bound...), args...)
+		if t.IsVariadic() {
+			return fv.CallSlice(in)
+		}
+		return fv.Call(in)
+	}), nil
+}
@@ -0,0 +1,256 @@
+package inject
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// binding is an entry of the Type map: either a mapped value or a provider
+// building the value, along with the options it was registered with.
+type binding struct {
+	value    reflect.Value
+	provider *provider
+	// supply, if set, returns the value of a binding of MapSupplier.
+	supply func() reflect.Value
+	// ref, if set, is the injector a binding of MapRef resolves from.
+	ref Injector
+	// pc is the program counter of the call registering the binding,
+	// resolved to a file:line by source only when it is reported.
+	pc uintptr
+	// alias is the type the binding has been aliased from, see Alias.
+	alias reflect.Type
+
+	copyOnGet bool
+	immutable bool
+	shared    bool
+	weak      bool
+	retry     *retry
+	breaker   *breaker
+	cache     *lru
+	rotation  *rotation
+	keys      []reflect.Type
+	serial    *serialLock
+	optional  bool
+	strict    bool
+	name      string
+	groups    []string
+	priority  int
+
+	onCreate  []func(interface{}) error
+	onDestroy []func(interface{}) error
+	onExpire  []func(interface{}) error
+}
+
+// BindOption configures a single binding when it is registered with MapWith,
+// MapToWith, SetWith or Provide.
+type BindOption func(*binding)
+
+func newBinding(val reflect.Value, p *provider, opts []BindOption) *binding {
+	e := &binding{value: val, provider: p}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// bind publishes e as the binding of t, replacing any previous binding of
+// t with the same name, or adds it to its groups. A value mapped to t is
+// not replaced by a provider of t: mapped values take precedence.
+func (i *injector) bind(t reflect.Type, e *binding) {
+	e.pc = registrationPC()
+	var report func()
+	i.update(func(b *bindings) {
+		defer func() { report = i.grown(b, t, e) }()
+		if len(e.groups) > 0 {
+			if b.own(ownsGroups) {
+				b.groups = cloneMap(b.groups)
+			}
+			for _, g := range e.groups {
+				// copied since the slice is shared with older snapshots
+				members := make([]member, len(b.groups[g]), len(b.groups[g])+1)
+				copy(members, b.groups[g])
+				b.groups[g] = append(members, member{t, e})
+			}
+			return
+		}
+		if e.name != "" {
+			if b.own(ownsNamed) {
+				b.named = cloneMap(b.named)
+			}
+			b.named[namedKey{t, e.name}] = e
+			return
+		}
+		if old, ok := b.entry(t); ok && e.provider != nil && old.provider == nil && old.ref == nil {
+			return
+		}
+		b.set(t, e)
+		b.indexImplementer(t)
+	})
+	if report != nil {
+		report()
+	}
+}
+
+var pkgPrefix = reflect.TypeOf(injector{}).PkgPath() + "."
+
+// registrationPC returns the program counter of the first caller outside
+// of the package, which is where a binding is being registered. The stack
+// is unwound a few frames at a time, since the registering call is rarely
+// more than a few frames away, and only the names of the functions are
+// looked up, the file:line is left to source.
+func registrationPC() uintptr {
+	var pcs [2]uintptr
+	// the caller of bind belongs to the package
+	for skip := 4; ; skip += len(pcs) {
+		n := runtime.Callers(skip, pcs[:])
+		for _, pc := range pcs[:n] {
+			// Callers reports a pc for every inlined call as well
+			if f := runtime.FuncForPC(pc - 1); f == nil || !strings.HasPrefix(f.Name(), pkgPrefix) {
+				return pc
+			}
+		}
+		if n < len(pcs) {
+			return 0
+		}
+	}
+}
+
+// source returns the file:line the binding was registered at, or "" if it
+// is not known.
+func (e *binding) source() string {
+	if e.pc == 0 {
+		return ""
+	}
+	f, _ := runtime.CallersFrames([]uintptr{e.pc}).Next()
+	if f.File == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", filepath.Base(f.File), f.Line)
+}
+
+// get returns the value handed out for a resolution of the binding, given
+// the mapped or provided value val.
+func (e *binding) get(val reflect.Value) reflect.Value {
+	if e.copyOnGet {
+		return deepCopy(val)
+	}
+	return val
+}
+
+// CopyOnGet returns a BindOption handing out a deep copy of the bound value
+// for every resolution, so that a consumer mutating a shared configuration
+// struct does not affect the other consumers.
+func CopyOnGet() BindOption {
+	return func(e *binding) {
+		e.copyOnGet = true
+	}
+}
+
+// deepCopy returns a copy of v that shares no pointer, slice, map or
+// interface contents with v. Unexported fields of structs are copied
+// shallowly, as reflect cannot set them; channels and functions are
+// shared.
+func deepCopy(v reflect.Value) reflect.Value {
+	return copyValue(v, make(map[pointer]reflect.Value))
+}
+
+// pointer identifies a pointer copied by copyValue. The address alone is
+// not enough: a pointer to a struct and one to its first field, or two
+// pointers to zero-size values, share it with different types.
+type pointer struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// copyValue copies v. seen maps the pointers already copied to their copy,
+// which preserves aliasing and terminates on cyclic data.
+func copyValue(v reflect.Value, seen map[pointer]reflect.Value) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+
+	c := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		key := pointer{v.Pointer(), v.Type()}
+		if p, ok := seen[key]; ok {
+			return p
+		}
+		p := reflect.New(v.Type().Elem())
+		seen[key] = p
+		p.Elem().Set(copyValue(v.Elem(), seen))
+		return p
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c.Set(copyValue(v.Elem(), seen))
+	case reflect.Struct:
+		c.Set(v)
+		for n := 0; n < v.NumField(); n++ {
+			if f := c.Field(n); f.CanSet() {
+				f.Set(copyValue(v.Field(n), seen))
+			}
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+		for n := 0; n < v.Len(); n++ {
+			c.Index(n).Set(copyValue(v.Index(n), seen))
+		}
+	case reflect.Array:
+		for n := 0; n < v.Len(); n++ {
+			c.Index(n).Set(copyValue(v.Index(n), seen))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(copyValue(iter.Key(), seen), copyValue(iter.Value(), seen))
+		}
+	default:
+		c.Set(v)
+	}
+	return c
+}
+
+// DefensiveCopies returns an Option handing out a deep copy of bindings of
+// non-pointer struct types for every resolution, so that consumers never
+// share the slices, maps or pointers such a struct holds. Since copying is
+// not free, a warning is logged, once per type, when a struct of at least
+// largeSize bytes is copied repeatedly; a pointer binding is usually a
+// better fit for those.
+func DefensiveCopies(largeSize uintptr) Option {
+	return func(i *injector) {
+		i.copies = make(map[reflect.Type]int)
+		i.largeSize = largeSize
+	}
+}
+
+// handOut returns the value of a resolution of t, copying non-pointer
+// structs when defensive copies are enabled.
+func (i *injector) handOut(t reflect.Type, e *binding, val reflect.Value) reflect.Value {
+	if e.copyOnGet || i.copies == nil || val.Kind() != reflect.Struct {
+		return e.get(val)
+	}
+
+	i.mu.Lock()
+	i.copies[t]++
+	warn := i.copies[t] == 2 && val.Type().Size() >= i.largeSize
+	i.mu.Unlock()
+	if warn {
+		i.warnf("struct binding %v of %d bytes is copied on every resolution, consider binding a pointer", t, val.Type().Size())
+	}
+	return deepCopy(val)
+}
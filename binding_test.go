@@ -0,0 +1,122 @@
+package inject_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type settings struct {
+	Name  string
+	Tags  []string
+	Limit map[string]int
+	Inner *Config
+	Self  *settings
+}
+
+func Test_InjectorCopyOnGet(t *testing.T) {
+	original := &settings{
+		Name:  "original",
+		Tags:  []string{"a"},
+		Limit: map[string]int{"rps": 1},
+		Inner: &Config{Name: "inner"},
+	}
+	original.Self = original
+
+	injector := inject.New()
+	injector.MapWith(original, inject.CopyOnGet())
+	injector.Provide(func() *Config { return &Config{Name: "provided"} }, inject.CopyOnGet())
+
+	_, err := injector.Invoke(func(s *settings, c *Config) {
+		s.Name = "changed"
+		s.Tags[0] = "changed"
+		s.Limit["rps"] = 2
+		s.Inner.Name = "changed"
+		expect(t, s.Self, s)
+		c.Name = "changed"
+	})
+	expect(t, err, nil)
+
+	_, err = injector.Invoke(func(s *settings, c *Config) {
+		expect(t, s == original, false)
+		expect(t, s.Name, "original")
+		expect(t, s.Tags[0], "a")
+		expect(t, s.Limit["rps"], 1)
+		expect(t, s.Inner.Name, "inner")
+		expect(t, c.Name, "provided")
+	})
+	expect(t, err, nil)
+}
+
+type counted struct {
+	N int
+}
+
+func Test_InjectorCopyOnGetSharedAddress(t *testing.T) {
+	in := &counted{N: 1}
+	type holder struct {
+		In *counted
+		N  *int
+	}
+	injector := inject.New()
+	injector.MapWith(holder{in, &in.N}, inject.CopyOnGet())
+
+	_, err := injector.Invoke(func(h holder) {
+		expect(t, h.In == in, false)
+		expect(t, h.In.N, 1)
+		expect(t, *h.N, 1)
+	})
+	expect(t, err, nil)
+}
+
+type logRecorder struct {
+	lines []string
+}
+
+func (l *logRecorder) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+type largeConfig struct {
+	Tags    []string
+	Padding [64]byte
+}
+
+func Test_InjectorDefensiveCopies(t *testing.T) {
+	log := &logRecorder{}
+	injector := inject.New(inject.DefensiveCopies(80), inject.WithLogger(log))
+	injector.Map(largeConfig{Tags: []string{"a"}})
+	injector.Map(settings{Tags: []string{"a"}})
+
+	for n := 0; n < 3; n++ {
+		_, err := injector.Invoke(func(c largeConfig, s settings) {
+			expect(t, c.Tags[0], "a")
+			expect(t, s.Tags[0], "a")
+			c.Tags[0] = "changed"
+			s.Tags[0] = "changed"
+		})
+		expect(t, err, nil)
+	}
+	expect(t, len(log.lines), 1)
+	expect(t, strings.Contains(log.lines[0], "inject_test.largeConfig"), true)
+}
+
+func Test_InjectorBindingSource(t *testing.T) {
+	injector := inject.New()
+	injector.Map("name")
+	injector.MapTo(&strings.Builder{}, (*fmt.Stringer)(nil))
+	inject.MapSupplier(injector, func() int { return 1 })
+	inject.ProvideFn0(injector, func() (*Config, error) { return &Config{}, nil })
+	expect(t, injector.Alias(reflect.TypeOf(0), reflect.TypeOf(int64(0))), nil)
+
+	sources := injector.Select(func(inject.Binding) bool { return true })
+	expect(t, len(sources), 5)
+	for _, b := range sources {
+		if !strings.HasPrefix(b.Source, "binding_test.go:") {
+			t.Errorf("%v registered at %q", b.Type, b.Source)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package inject
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is matched by errors.Is for the errors of providers whose
+// circuit breaker is open.
+var ErrCircuitOpen = errors.New("inject: circuit open")
+
+// CircuitOpenError is returned instead of invoking the provider of Type
+// while its circuit breaker is open. Err is the last error of the
+// provider and Until the time the next attempt is let through.
+type CircuitOpenError struct {
+	Type  reflect.Type
+	Until time.Time
+	Err   error
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("Circuit open for type %v until %v: %v", e.Type, e.Until.Format(time.RFC3339), e.Err)
+}
+
+// Is reports whether target is ErrCircuitOpen.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+func (e *CircuitOpenError) Unwrap() error {
+	return e.Err
+}
+
+// breaker tracks the consecutive failures of a provider.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	last     error
+	until    time.Time
+	probing  bool
+}
+
+// Breaker returns a BindOption opening a circuit breaker around the
+// provider of the binding once it failed threshold times in a row. While
+// the breaker is open, resolutions fail fast with a CircuitOpenError
+// instead of invoking the provider. After cooldown a single resolution
+// probes the provider: the breaker closes if it succeeds and opens again
+// if it fails. Breaker is meant for Transient providers invoked for every
+// resolution; failures to resolve the arguments of the provider are not
+// counted.
+// It panics if threshold is not positive.
+func Breaker(threshold int, cooldown time.Duration) BindOption {
+	if threshold <= 0 {
+		panic("Called inject.Breaker with a threshold that is not positive")
+	}
+	return func(e *binding) {
+		e.breaker = &breaker{threshold: threshold, cooldown: cooldown}
+	}
+}
+
+// allow returns a CircuitOpenError if the provider of t must not be
+// invoked. A nil breaker allows every invocation.
+func (b *breaker) allow(t reflect.Type) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return nil
+	}
+	if b.probing || time.Now().Before(b.until) {
+		return &CircuitOpenError{t, b.until, b.last}
+	}
+	b.probing = true
+	return nil
+}
+
+// record updates the breaker with the outcome of an invocation.
+func (b *breaker) record(err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if err == nil {
+		b.failures, b.last = 0, nil
+		return
+	}
+	b.failures++
+	b.last = err
+	if b.failures >= b.threshold {
+		b.until = time.Now().Add(b.cooldown)
+	}
+}
@@ -0,0 +1,84 @@
+package inject_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorBreaker(t *testing.T) {
+	down := true
+	calls := 0
+	injector := inject.New()
+	injector.Provide(func() (int, error) {
+		calls++
+		if down {
+			return 0, errors.New("down")
+		}
+		return calls, nil
+	}, inject.Transient(), inject.Breaker(2, 20*time.Millisecond))
+
+	for n := 0; n < 4; n++ {
+		_, err := injector.Invoke(func(int) {})
+		refute(t, err, nil)
+	}
+	expect(t, calls, 2)
+
+	_, err := injector.Invoke(func(int) {})
+	expect(t, errors.Is(err, inject.ErrCircuitOpen), true)
+	var ce *inject.CircuitOpenError
+	expect(t, errors.As(err, &ce), true)
+	expect(t, ce.Err.Error(), "down")
+
+	time.Sleep(30 * time.Millisecond)
+	down = false
+	var n int
+	expect(t, injector.Populate(&n), nil)
+	expect(t, n, 3)
+	expect(t, injector.Populate(&n), nil)
+	expect(t, n, 4)
+}
+
+func Test_InjectorBreakerProbeWithoutArguments(t *testing.T) {
+	down, depDown := true, false
+	injector := inject.New()
+	injector.Provide(func() (string, error) {
+		if depDown {
+			return "", errors.New("dependency down")
+		}
+		return "up", nil
+	}, inject.Transient())
+	injector.Provide(func(s string) (int, error) {
+		if down {
+			return 0, errors.New("down")
+		}
+		return len(s), nil
+	}, inject.Transient(), inject.Breaker(1, 10*time.Millisecond))
+
+	_, err := injector.Invoke(func(int) {})
+	refute(t, err, nil)
+	time.Sleep(20 * time.Millisecond)
+
+	depDown = true
+	_, err = injector.Invoke(func(int) {})
+	expect(t, errors.Is(err, inject.ErrCircuitOpen), false)
+
+	down, depDown = false, false
+	var n int
+	expect(t, injector.Populate(&n), nil)
+	expect(t, n, 2)
+}
+
+func Test_InjectorTransient(t *testing.T) {
+	calls := 0
+	injector := inject.New()
+	injector.Provide(func() int { calls++; return calls }, inject.Transient())
+
+	var a, b int
+	expect(t, injector.Populate(&a), nil)
+	expect(t, injector.Populate(&b), nil)
+	expect(t, a, 1)
+	expect(t, b, 2)
+}
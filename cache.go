@@ -0,0 +1,139 @@
+package inject
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// lru caches the values built by a provider, keyed by its arguments.
+type lru struct {
+	max int
+	ttl time.Duration
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[interface{}]*list.Element
+}
+
+type lruEntry struct {
+	key     interface{}
+	val     reflect.Value
+	expires time.Time
+}
+
+// CacheLRU returns a BindOption caching the values built by the provider
+// of the binding, keyed by the values of its arguments, so that a
+// Transient provider such as func(TenantID) *Client reuses the client of
+// a tenant across requests. At most maxEntries values are kept, the least
+// recently used being evicted first, and a value older than ttl is built
+// again; a zero ttl keeps values until they are evicted. Values of the
+// cache are never closed, by Dispose, by the scopes of Enter or when they
+// are evicted, since consumers may still hold them, and the OnDestroy
+// hooks of the binding are not called for them.
+// Arguments of types that are not comparable, such as slices, or holding
+// values that are not, bypass the cache. Contexts are left out of the key,
+// since every call gets its own.
+// It panics if maxEntries is not positive.
+func CacheLRU(maxEntries int, ttl time.Duration) BindOption {
+	if maxEntries <= 0 {
+		panic("Called inject.CacheLRU with a size that is not positive")
+	}
+	return func(e *binding) {
+		e.cache = &lru{
+			max:     maxEntries,
+			ttl:     ttl,
+			order:   list.New(),
+			entries: make(map[interface{}]*list.Element),
+		}
+	}
+}
+
+// key returns the cache key of the arguments in. ok is false when there is
+// no cache or one of the arguments is not comparable.
+func (c *lru) key(in []reflect.Value) (key interface{}, ok bool) {
+	if c == nil {
+		return nil, false
+	}
+	return cacheKey(in)
+}
+
+// cacheKey returns a comparable key made of the values in, contexts left
+// out. ok is false when one of them is not comparable.
+func cacheKey(in []reflect.Value) (key interface{}, ok bool) {
+	k := reflect.New(reflect.ArrayOf(len(in), reflect.TypeOf((*interface{})(nil)).Elem())).Elem()
+	for n, v := range in {
+		if v.Type().Implements(contextType) {
+			continue
+		}
+		if !keyable(v) {
+			return nil, false
+		}
+		k.Index(n).Set(v)
+	}
+	return k.Interface(), true
+}
+
+// keyable reports whether v can be compared with ==, which takes the
+// dynamic values of the interfaces it holds to be comparable as well: the
+// type of a struct with an interface field is comparable, but comparing it
+// panics when the field holds a slice.
+func keyable(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface:
+		return v.IsNil() || keyable(v.Elem())
+	case reflect.Struct:
+		for n := 0; n < v.NumField(); n++ {
+			if !keyable(v.Field(n)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Array:
+		for n := 0; n < v.Len(); n++ {
+			if !keyable(v.Index(n)) {
+				return false
+			}
+		}
+		return true
+	}
+	return v.Type().Comparable()
+}
+
+// get returns the live value cached for key.
+func (c *lru) get(key interface{}) (reflect.Value, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return reflect.Value{}, false
+	}
+	entry := el.Value.(*lruEntry)
+	if c.ttl > 0 && time.Now().After(entry.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return reflect.Value{}, false
+	}
+	c.order.MoveToFront(el)
+	return entry.val, true
+}
+
+// put caches val for key, evicting the least recently used value when the
+// cache is full.
+func (c *lru) put(key interface{}, val reflect.Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &lruEntry{key, val, time.Now().Add(c.ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	if c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
@@ -0,0 +1,88 @@
+package inject_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codegangsta/inject"
+)
+
+type TenantID string
+
+type client struct {
+	tenant TenantID
+}
+
+func Test_InjectorCacheLRU(t *testing.T) {
+	built := 0
+	root := inject.New()
+	root.Provide(func(id TenantID) *client {
+		built++
+		return &client{id}
+	}, inject.Transient(), inject.CacheLRU(2, 0))
+
+	get := func(id TenantID) *client {
+		req := inject.New()
+		req.SetParent(root)
+		req.Map(id)
+		var c *client
+		expect(t, req.Populate(&c), nil)
+		return c
+	}
+
+	a := get("a")
+	expect(t, get("a") == a, true)
+	get("b")
+	expect(t, built, 2)
+	get("c") // evicts a
+	expect(t, get("a") == a, false)
+	expect(t, built, 4)
+}
+
+func Test_InjectorCacheLRUExpiry(t *testing.T) {
+	built := 0
+	injector := inject.New()
+	injector.Map(TenantID("a"))
+	injector.Provide(func(id TenantID) *client {
+		built++
+		return &client{id}
+	}, inject.Transient(), inject.CacheLRU(1, 10*time.Millisecond))
+
+	var c *client
+	expect(t, injector.Populate(&c), nil)
+	expect(t, injector.Populate(&c), nil)
+	expect(t, built, 1)
+	time.Sleep(20 * time.Millisecond)
+	expect(t, injector.Populate(&c), nil)
+	expect(t, built, 2)
+}
+
+type tenantFilter struct {
+	tenant TenantID
+	match  interface{}
+}
+
+func Test_InjectorCacheLRUKeys(t *testing.T) {
+	built := 0
+	injector := inject.New()
+	injector.Provide(func(ctx context.Context, f tenantFilter) *client {
+		built++
+		return &client{f.tenant}
+	}, inject.Transient(), inject.CacheLRU(4, 0))
+
+	injector.Map(tenantFilter{"a", "eu"})
+	var c *client
+	expect(t, injector.Populate(&c), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := injector.InvokeWithContext(ctx, func(*client) {})
+	expect(t, err, nil)
+	expect(t, built, 1)
+
+	// comparing the filters would panic on the slice
+	injector.Map(tenantFilter{"a", []string{"eu"}})
+	expect(t, injector.Populate(&c), nil)
+	expect(t, injector.Populate(&c), nil)
+	expect(t, built, 3)
+}
@@ -0,0 +1,14 @@
+package inject
+
+import "reflect"
+
+// bidirectional returns the bidirectional channel type a value of the
+// directional channel type t can be resolved from, so that a mapped
+// chan T is injected into arguments and fields of type <-chan T or
+// chan<- T.
+func bidirectional(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Chan || t.ChanDir() == reflect.BothDir {
+		return nil, false
+	}
+	return reflect.ChanOf(reflect.BothDir, t.Elem()), true
+}
@@ -0,0 +1,29 @@
+package inject_test
+
+import (
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorChannelDirection(t *testing.T) {
+	events := make(chan string, 1)
+	injector := inject.New()
+	injector.Map(events)
+
+	_, err := injector.Invoke(func(out chan<- string, in <-chan string) {
+		out <- "event"
+		expect(t, <-in, "event")
+	})
+	expect(t, err, nil)
+	expect(t, injector.Validate(func(<-chan string) {}), nil)
+
+	s := struct {
+		In <-chan string `inject`
+	}{}
+	expect(t, injector.Apply(&s), nil)
+	expect(t, s.In == nil, false)
+
+	_, err = injector.Invoke(func(<-chan int) {})
+	refute(t, err, nil)
+}
@@ -0,0 +1,50 @@
+package inject
+
+import "reflect"
+
+// Child returns a new injector whose parent is the injector, replacing the
+// New and SetParent pair. The child starts with the options the injector
+// was configured with, such as its logger, test mode, debug mode, tag
+// name, conversions or provider concurrency limit, which opts then
+// override; Isolated drops them all. The delegation policy governs how
+// the injector asks its own parent and is not inherited.
+// The child has its own bindings, scope and disposal; the providers it
+// shares with the injector keep running under the limit of the injector.
+func (i *injector) Child(opts ...Option) Injector {
+	child := New(append([]Option{i.inherit}, opts...)...)
+	child.SetParent(i)
+	return child
+}
+
+// Isolated returns an Option for Child dropping the configuration the
+// child would inherit from its parent, leaving the options that follow.
+func Isolated() Option {
+	return (&injector{}).inherit
+}
+
+// inherit is the Option configuring child like the injector.
+func (i *injector) inherit(child *injector) {
+	child.logger = i.logger
+	child.debug = i.debug
+	child.conversions = i.conversions
+	child.implementations = i.implementations
+	child.strictApply = i.strictApply
+	child.labels = i.labels
+	child.maxBindings = i.maxBindings
+	child.exceeded = i.exceeded
+	child.policies = i.policies
+	if i.history != nil {
+		child.history = newRing(len(i.history.buf))
+	}
+	child.statistics = i.statistics
+	child.slots = i.slots
+	child.largeSize = i.largeSize
+	child.tag = i.tag
+	child.presets = i.presets
+	if i.copies != nil {
+		child.copies = make(map[reflect.Type]int)
+	}
+	if i.fakes != nil {
+		child.fakes = make(map[reflect.Type]reflect.Value)
+	}
+}
@@ -0,0 +1,61 @@
+package inject_test
+
+import (
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorChild(t *testing.T) {
+	parent := inject.New(inject.Conversions())
+	parent.Map(int64(7))
+
+	child := parent.Child()
+	_, err := child.Invoke(func(n int) { expect(t, n, 7) })
+	expect(t, err, nil)
+
+	child.Map("child")
+	_, err = parent.Invoke(func(string) {})
+	refute(t, err, nil)
+
+	fakes := inject.New(inject.TestMode()).Child()
+	_, err = fakes.Invoke(func(func() Plugin) {})
+	expect(t, err, nil)
+}
+
+func Test_InjectorChildOverride(t *testing.T) {
+	parentLog, childLog := &logRecorder{}, &logRecorder{}
+	parent := inject.New(inject.DefensiveCopies(0), inject.WithLogger(parentLog))
+	child := parent.Child(inject.WithLogger(childLog))
+	child.Map(largeConfig{})
+
+	for n := 0; n < 2; n++ {
+		_, err := child.Invoke(func(largeConfig) {})
+		expect(t, err, nil)
+	}
+	expect(t, len(parentLog.lines), 0)
+	expect(t, len(childLog.lines), 1)
+}
+
+func Test_InjectorChildInheritsTagName(t *testing.T) {
+	parent := inject.New(inject.TagName("di"))
+	parent.Map("tagged")
+	s := struct {
+		A string `di:""`
+		B string `inject`
+	}{}
+
+	child := parent.Child()
+	expect(t, child.Apply(&s), nil)
+	expect(t, s.A, "tagged")
+	expect(t, s.B, "")
+
+	isolated := parent.Child(inject.Isolated())
+	expect(t, isolated.Apply(&s), nil)
+	expect(t, s.B, "tagged")
+
+	conv := inject.New(inject.Conversions()).Child(inject.Isolated())
+	conv.Map(int64(1))
+	_, err := conv.Invoke(func(int) {})
+	refute(t, err, nil)
+}
@@ -0,0 +1,145 @@
+package inject
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Commands routes command line arguments to subcommands whose functions
+// get their arguments injected, so that operational tools can reuse the
+// wiring of the service they belong to.
+type Commands struct {
+	// Stdout is mapped to io.Writer for the invoked commands and receives
+	// the flag parsing messages. It defaults to os.Stdout.
+	Stdout io.Writer
+
+	inj  Injector
+	cmds map[string]command
+}
+
+type command struct {
+	fn    interface{}
+	flags interface{}
+}
+
+// NewCommands returns a command router resolving the arguments of its
+// commands from inj.
+func NewCommands(inj Injector) *Commands {
+	return &Commands{
+		Stdout: os.Stdout,
+		inj:    inj,
+		cmds:   make(map[string]command),
+	}
+}
+
+// Add registers fn as the subcommand name. flags is either nil or a
+// pointer to a struct whose fields tagged with `flag:"name"` define the
+// flags of the subcommand, with the current field values as defaults and
+// an optional `usage:"..."` tag. Supported field types are string, bool,
+// int, int64, uint, uint64, float64 and time.Duration.
+// It panics if fn is not a function or flags is not a pointer to a struct.
+func (c *Commands) Add(name string, fn interface{}, flags interface{}) *Commands {
+	if reflect.TypeOf(fn).Kind() != reflect.Func {
+		panic("Called inject.Commands.Add with a value that is not a function")
+	}
+	if flags != nil {
+		t := reflect.TypeOf(flags)
+		if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+			panic("Called inject.Commands.Add with flags that are not a pointer to a struct")
+		}
+	}
+	c.cmds[name] = command{fn, flags}
+	return c
+}
+
+// Run parses args, whose first element names the subcommand, and invokes
+// the subcommand from a child injector of the router's injector in which
+// the flags struct is mapped to its pointer type, Stdout to io.Writer and
+// the remaining positional arguments to []string.
+// Returns an error if the subcommand is unknown, if its flags cannot be
+// parsed, if its arguments cannot be injected, or the last return value of
+// the subcommand if it is a non-nil error.
+func (c *Commands) Run(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Missing command, expected one of %s", c.names())
+	}
+	cmd, ok := c.cmds[args[0]]
+	if !ok {
+		return fmt.Errorf("Unknown command %q, expected one of %s", args[0], c.names())
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(c.Stdout)
+	if cmd.flags != nil {
+		if err := defineFlags(fs, reflect.ValueOf(cmd.flags).Elem()); err != nil {
+			return err
+		}
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	child := c.inj.Child()
+	child.MapTo(c.Stdout, (*io.Writer)(nil))
+	child.Map(fs.Args())
+	if cmd.flags != nil {
+		child.Map(cmd.flags)
+	}
+
+	out, err := child.Invoke(cmd.fn)
+	if err != nil {
+		return err
+	}
+	return lastError(out)
+}
+
+func (c *Commands) names() string {
+	names := make([]string, 0, len(c.cmds))
+	for name := range c.cmds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// defineFlags defines a flag on fs for every field of v tagged with flag.
+func defineFlags(fs *flag.FlagSet, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := sf.Tag.Get("flag")
+		if name == "" || sf.PkgPath != "" {
+			continue
+		}
+		usage := sf.Tag.Get("usage")
+
+		p := v.Field(i).Addr().Interface()
+		switch p := p.(type) {
+		case *string:
+			fs.StringVar(p, name, *p, usage)
+		case *bool:
+			fs.BoolVar(p, name, *p, usage)
+		case *int:
+			fs.IntVar(p, name, *p, usage)
+		case *int64:
+			fs.Int64Var(p, name, *p, usage)
+		case *uint:
+			fs.UintVar(p, name, *p, usage)
+		case *uint64:
+			fs.Uint64Var(p, name, *p, usage)
+		case *float64:
+			fs.Float64Var(p, name, *p, usage)
+		case *time.Duration:
+			fs.DurationVar(p, name, *p, usage)
+		default:
+			return fmt.Errorf("Unsupported type %v for flag %q", sf.Type, name)
+		}
+	}
+	return nil
+}
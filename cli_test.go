@@ -0,0 +1,41 @@
+package inject_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/codegangsta/inject"
+)
+
+type migrateFlags struct {
+	DryRun  bool          `flag:"dry-run" usage:"only print the migrations"`
+	Steps   int           `flag:"steps"`
+	Timeout time.Duration `flag:"timeout"`
+}
+
+func Test_CommandsRun(t *testing.T) {
+	injector := inject.New()
+	injector.Map(&Config{Name: "db"})
+
+	var out bytes.Buffer
+	cmds := inject.NewCommands(injector)
+	cmds.Stdout = &out
+	cmds.Add("migrate", func(w io.Writer, f *migrateFlags, c *Config, args []string) error {
+		fmt.Fprintf(w, "%s %v %d %v %v", c.Name, f.DryRun, f.Steps, f.Timeout, args)
+		return nil
+	}, &migrateFlags{Steps: 1})
+	cmds.Add("fail", func() error { return errors.New("boom") }, nil)
+
+	err := cmds.Run([]string{"migrate", "-dry-run", "-timeout", "2s", "up"})
+	expect(t, err, nil)
+	expect(t, out.String(), "db true 1 2s [up]")
+
+	expect(t, cmds.Run([]string{"fail"}).Error(), "boom")
+	refute(t, cmds.Run([]string{"unknown"}), nil)
+	refute(t, cmds.Run(nil), nil)
+	refute(t, cmds.Run([]string{"migrate", "-unknown"}), nil)
+}
@@ -0,0 +1,93 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// collectable reports whether t is a slice of functional options, e.g. a
+// []Option where Option is a func(*Config), which lookup collects when it
+// is not bound. Options take the pointer they configure and return nothing
+// or an error: slices of other funcs, e.g. []http.HandlerFunc, are not
+// collected.
+func collectable(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Func {
+		return false
+	}
+	opt := t.Elem()
+	if opt.NumIn() != 1 || opt.In(0).Kind() != reflect.Ptr || opt.IsVariadic() {
+		return false
+	}
+	return opt.NumOut() == 0 || opt.NumOut() == 1 && opt.Out(0) == errorType
+}
+
+// collect resolves a slice of functional options of type t as part of c,
+// from every binding of its element type: the one of its type, the named
+// ones, by name, and the members of any group, see Group, that the
+// injector and its parents hold. The options of the parents come first,
+// so that those of the injector, applied last, win. Modules contribute
+// options to a shared constructor with Named or Group without replacing
+// each other, e.g. MapWith(Option(withTimeout), Group("http")). Returns a
+// NotFoundError when no binding contributes an option.
+func (i *injector) collect(t reflect.Type, c *call) (reflect.Value, error) {
+	options := i.options(t.Elem(), c, make(map[*binding]bool))
+	if len(options) == 0 {
+		return reflect.Value{}, i.notFound(t)
+	}
+	vals := reflect.MakeSlice(t, 0, len(options))
+	for _, m := range options {
+		val, err := m.value(c)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		vals = reflect.Append(vals, val)
+	}
+	c.step(TraceAdapt, i, t, fmt.Sprintf("%d options collected", vals.Len()))
+	return vals, nil
+}
+
+// options returns the bindings of the injector and its parents that
+// collect gathers for the option type t, skipping those in seen.
+func (i *injector) options(t reflect.Type, c *call, seen map[*binding]bool) []groupMember {
+	var all []groupMember
+	for _, p := range c.bindings(i).parents {
+		if pi, ok := p.inj.(*injector); ok && i.delegates(t) && p.allows(t) {
+			all = append(all, pi.options(t, c, seen)...)
+		}
+	}
+
+	b := c.bindings(i)
+	add := func(e *binding) {
+		if !seen[e] {
+			seen[e] = true
+			all = append(all, groupMember{member{t, e}, i})
+		}
+	}
+	if e, ok := b.entry(t); ok {
+		add(e)
+	}
+	var names []string
+	for k := range b.named {
+		if k.typ == t {
+			names = append(names, k.name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		add(b.named[namedKey{t, name}])
+	}
+	groups := make([]string, 0, len(b.groups))
+	for g := range b.groups {
+		groups = append(groups, g)
+	}
+	sort.Strings(groups)
+	for _, g := range groups {
+		for _, m := range b.groups[g] {
+			if m.typ == t {
+				add(m.e)
+			}
+		}
+	}
+	return all
+}
@@ -0,0 +1,54 @@
+package inject_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type ServerOption func(*[]string)
+
+func newServer(opts []ServerOption) string {
+	var applied []string
+	for _, opt := range opts {
+		opt(&applied)
+	}
+	return strings.Join(applied, ",")
+}
+
+func option(name string) ServerOption {
+	return func(applied *[]string) { *applied = append(*applied, name) }
+}
+
+func Test_InjectorCollectOptions(t *testing.T) {
+	parent := inject.New()
+	parent.Map(option("defaults"))
+	injector := parent.Child()
+	injector.MapWith(option("tls"), inject.Group("security"))
+	injector.MapWith(option("metrics"), inject.Named("metrics"))
+	injector.MapWith(option("auth"), inject.Group("security"))
+	injector.Provide(newServer)
+
+	out, err := injector.Invoke(func(s string) string { return s })
+	expect(t, err, nil)
+	expect(t, out[0].String(), "defaults,metrics,tls,auth")
+
+	_, err = inject.New().Invoke(newServer)
+	expect(t, errors.Is(err, inject.ErrNotFound), true)
+	expect(t, injector.Validate(newServer), nil)
+
+	injector.Map([]ServerOption{option("explicit")})
+	out, err = injector.Invoke(newServer)
+	expect(t, err, nil)
+	expect(t, out[0].String(), "explicit")
+}
+
+func Test_InjectorCollectOnlyOptions(t *testing.T) {
+	injector := inject.New()
+	injector.Map(func(string) {})
+
+	_, err := injector.Invoke(func([]func(string)) {})
+	expect(t, errors.Is(err, inject.ErrNotFound), true)
+}
@@ -0,0 +1,79 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// InvokeConcurrently invokes every function of fns on its own goroutine
+// with InvokeWithContext and waits for all of them to return. A function
+// fails when its arguments cannot be resolved or when its last return
+// value is a non-nil error; the other values it returns are discarded.
+// The first failure cancels the context the other functions and the
+// providers they build receive, and is the error returned.
+// It panics if one of fns is not a function.
+func (inj *injector) InvokeConcurrently(ctx context.Context, fns ...interface{}) error {
+	return inj.invokeConcurrently(ctx, false, fns)
+}
+
+// InvokeConcurrentlyJoined works like InvokeConcurrently but runs every
+// function to completion even once one of them failed, and returns the
+// errors of every failed function joined in the order of fns.
+// It panics if one of fns is not a function.
+func (inj *injector) InvokeConcurrentlyJoined(ctx context.Context, fns ...interface{}) error {
+	return inj.invokeConcurrently(ctx, true, fns)
+}
+
+// invokeConcurrently invokes fns in parallel, cancelling the others on the
+// first failure unless keepGoing is set.
+func (inj *injector) invokeConcurrently(ctx context.Context, keepGoing bool, fns []interface{}) error {
+	// checked before starting any goroutine, whose panic would crash the
+	// program instead of reaching the caller
+	for _, f := range fns {
+		if t := reflect.TypeOf(f); t == nil || t.Kind() != reflect.Func {
+			caller := "InvokeConcurrently"
+			if keepGoing {
+				caller = "InvokeConcurrentlyJoined"
+			}
+			panic("Called inject." + caller + " with a value that is not a function")
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		first error
+		errs  = make([]error, len(fns))
+	)
+	for n, f := range fns {
+		wg.Add(1)
+		go func(n int, f interface{}) {
+			defer wg.Done()
+			out, err := inj.InvokeWithContext(ctx, f)
+			if err == nil {
+				err = lastError(out)
+			}
+			if err == nil {
+				return
+			}
+			errs[n] = err
+			once.Do(func() {
+				first = err
+				if !keepGoing {
+					cancel()
+				}
+			})
+		}(n, f)
+	}
+	wg.Wait()
+
+	if keepGoing {
+		return errors.Join(errs...)
+	}
+	return first
+}
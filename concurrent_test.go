@@ -0,0 +1,63 @@
+package inject_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorInvokeConcurrently(t *testing.T) {
+	injector := inject.New()
+	injector.Map("dep")
+	var ran int32
+	err := injector.InvokeConcurrently(context.Background(),
+		func(s string) { atomic.AddInt32(&ran, 1) },
+		func(s string) error { atomic.AddInt32(&ran, 1); return nil },
+	)
+	expect(t, err, nil)
+	expect(t, atomic.LoadInt32(&ran), int32(2))
+
+	boom := errors.New("boom")
+	err = injector.InvokeConcurrently(context.Background(),
+		func() error { return boom },
+		func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		},
+	)
+	expect(t, err, boom)
+}
+
+func Test_InjectorInvokeConcurrentlyJoined(t *testing.T) {
+	injector := inject.New()
+	cancelled := false
+	err := injector.InvokeConcurrentlyJoined(context.Background(),
+		func() error { return errors.New("first") },
+		func(int) {},
+		func(ctx context.Context) error {
+			cancelled = ctx.Err() != nil
+			return errors.New("third")
+		},
+	)
+	refute(t, err, nil)
+	expect(t, cancelled, false)
+	expect(t, errors.Is(err, inject.ErrNotFound), true)
+	msgs := strings.Split(err.Error(), "\n")
+	expect(t, len(msgs), 3)
+	expect(t, msgs[0], "first")
+	expect(t, msgs[2], "third")
+}
+
+func Test_InjectorInvokeConcurrentlyNotFunction(t *testing.T) {
+	injector := inject.New()
+	var ran int32
+	defer func() {
+		expect(t, recover(), "Called inject.InvokeConcurrently with a value that is not a function")
+		expect(t, atomic.LoadInt32(&ran), int32(0))
+	}()
+	injector.InvokeConcurrently(context.Background(), func() { atomic.AddInt32(&ran, 1) }, "not a function")
+}
@@ -0,0 +1,118 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// callType is the type of the argument of the providers synthesized by
+// BindIf and BindSplit, which resolves to the call they are invoked by so
+// that the chosen provider is invoked as part of it.
+var callType = reflect.TypeOf((*call)(nil))
+
+// BindIf binds either then or els depending on cond, which is evaluated
+// with the injector the first time the bound type is requested. This lets
+// a binding depend on other bindings, e.g. use a Redis cache only if a
+// Redis client is mapped.
+// then and els are either two values of the same type, which is the bound
+// type, or two providers whose first return types are identical, in which
+// case the chosen provider is invoked like one registered with Provide.
+// The choice and the resulting value are both memoized.
+// It panics if then and els do not have the same bound type.
+func (i *injector) BindIf(cond func(Injector) bool, then, els interface{}) TypeMapper {
+	t, err := boundType(then, els)
+	if err != nil {
+		panic(err.Error())
+	}
+	return i.bindIf(t, cond, then, els)
+}
+
+// BindIfTo is like BindIf but binds the interface type ifacePtr points to,
+// see MapTo, so that then and els, values or providers, may be of
+// different types implementing it, e.g. a Redis and an in-memory cache.
+// It panics if the type of then or els does not implement the interface.
+func (i *injector) BindIfTo(cond func(Injector) bool, then, els, ifacePtr interface{}) TypeMapper {
+	t := InterfaceOf(ifacePtr)
+	for _, v := range []interface{}{then, els} {
+		if vt := candidateType(v); vt == nil || !vt.Implements(t) {
+			panic(fmt.Sprintf("Called inject.BindIfTo with %v, which does not implement %v", vt, t))
+		}
+	}
+	return i.bindIf(t, cond, then, els)
+}
+
+// bindIf binds t to the provider choosing between then and els.
+func (i *injector) bindIf(t reflect.Type, cond func(Injector) bool, then, els interface{}) TypeMapper {
+	ft := reflect.FuncOf([]reflect.Type{callType}, []reflect.Type{t, errorType}, false)
+	i.bind(t, &binding{provider: &provider{fn: reflect.MakeFunc(ft, func(in []reflect.Value) []reflect.Value {
+		chosen := els
+		if cond(i) {
+			chosen = then
+		}
+		val, err := evaluate(in[0].Interface().(*call), chosen)
+		return results(t, val, err)
+	})}})
+	return i
+}
+
+// results returns the values returned by a synthesized provider of type t
+// for val and err.
+func results(t reflect.Type, val reflect.Value, err error) []reflect.Value {
+	if err != nil {
+		return []reflect.Value{reflect.Zero(t), reflect.ValueOf(&err).Elem()}
+	}
+	out := reflect.New(t).Elem()
+	out.Set(val)
+	return []reflect.Value{out, reflect.Zero(errorType)}
+}
+
+// evaluate returns v itself, or the first value returned by invoking v as
+// part of c, from the injector c started from, if v is a function, so that
+// a provider depending on the type being chosen for is reported as a
+// cycle. It reports the error returned last by v, if any.
+func evaluate(c *call, v interface{}) (reflect.Value, error) {
+	if reflect.TypeOf(v).Kind() != reflect.Func {
+		return reflect.ValueOf(v), nil
+	}
+	out, err := c.origin.invoke(v, c, nil)
+	if err == nil && len(out) > 1 {
+		err = lastError(out)
+	}
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return out[0], nil
+}
+
+// candidateType returns the type v binds, the first return type of v if v
+// is a provider.
+func candidateType(v interface{}) reflect.Type {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Func {
+		if t.NumOut() == 0 {
+			return nil
+		}
+		return t.Out(0)
+	}
+	return t
+}
+
+// boundType returns the type bound by then and els, see BindIf.
+func boundType(then, els interface{}) (reflect.Type, error) {
+	tt, et := reflect.TypeOf(then), reflect.TypeOf(els)
+	if tt == nil || et == nil {
+		return nil, fmt.Errorf("Called inject.BindIf with a nil value")
+	}
+	if tt.Kind() == reflect.Func && et.Kind() == reflect.Func {
+		if tt.NumOut() == 0 || et.NumOut() == 0 || tt.Out(0) != et.Out(0) {
+			return nil, fmt.Errorf("Called inject.BindIf with providers of different types %v and %v", tt, et)
+		}
+		return tt.Out(0), nil
+	}
+	if tt != et {
+		return nil, fmt.Errorf("Called inject.BindIf with values of different types %v and %v", tt, et)
+	}
+	return tt, nil
+}
@@ -0,0 +1,87 @@
+package inject_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type Cache interface {
+	Kind() string
+}
+
+type redisClient struct{}
+
+type redisCache struct{ client *redisClient }
+
+func (redisCache) Kind() string { return "redis" }
+
+type memoryCache struct{}
+
+func (memoryCache) Kind() string { return "memory" }
+
+func Test_InjectorBindIf(t *testing.T) {
+	hasRedis := func(inj inject.Injector) bool {
+		return inj.Get(reflect.TypeOf(&redisClient{})).IsValid()
+	}
+	newRedis := func(c *redisClient) Cache { return redisCache{c} }
+	newMemory := func() Cache { return memoryCache{} }
+
+	injector := inject.New()
+	injector.BindIf(hasRedis, newRedis, newMemory)
+	injector.Map(&redisClient{})
+	_, err := injector.Invoke(func(c Cache) {
+		expect(t, c.Kind(), "redis")
+	})
+	expect(t, err, nil)
+
+	injector = inject.New()
+	injector.BindIf(hasRedis, newRedis, newMemory)
+	_, err = injector.Invoke(func(c Cache) {
+		expect(t, c.Kind(), "memory")
+	})
+	expect(t, err, nil)
+
+	injector.BindIf(func(inject.Injector) bool { return false }, "then", "else")
+	expect(t, injector.Get(reflect.TypeOf("")).String(), "else")
+
+	defer func() {
+		refute(t, recover(), nil)
+	}()
+	injector.BindIf(hasRedis, "then", 42)
+}
+
+func Test_InjectorBindIfTo(t *testing.T) {
+	hasRedis := func(inj inject.Injector) bool {
+		return inj.Get(reflect.TypeOf(&redisClient{})).IsValid()
+	}
+
+	injector := inject.New()
+	injector.Map(&redisClient{})
+	injector.BindIfTo(hasRedis, redisCache{}, memoryCache{}, (*Cache)(nil))
+	_, err := injector.Invoke(func(c Cache) {
+		expect(t, c.Kind(), "redis")
+	})
+	expect(t, err, nil)
+
+	injector = inject.New()
+	injector.BindIfTo(hasRedis, func(c *redisClient) redisCache { return redisCache{c} }, memoryCache{}, (*Cache)(nil))
+	_, err = injector.Invoke(func(c Cache) {
+		expect(t, c.Kind(), "memory")
+	})
+	expect(t, err, nil)
+
+	defer func() {
+		refute(t, recover(), nil)
+	}()
+	injector.BindIfTo(hasRedis, memoryCache{}, "not a cache", (*Cache)(nil))
+}
+
+func Test_InjectorBindIfCycle(t *testing.T) {
+	injector := inject.New()
+	injector.BindIf(func(inject.Injector) bool { return true }, func(c Cache) Cache { return c }, func() Cache { return memoryCache{} })
+	_, err := injector.Invoke(func(Cache) {})
+	expect(t, errors.Is(err, inject.ErrCycle), true)
+}
@@ -0,0 +1,155 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// PostConstructor is implemented by types that need to finish their own
+// initialization once Construct has injected all of their dependencies.
+type PostConstructor interface {
+	PostConstruct() error
+}
+
+// construction tracks the state of a single Construct call. Dependencies
+// built along the way are shared by every struct of the graph that needs
+// them, and types still being built are remembered to detect cycles.
+type construction struct {
+	inj      *injector
+	call     *call
+	built    map[reflect.Type]reflect.Value
+	visiting map[reflect.Type]bool
+}
+
+// Construct allocates and wires the struct ptr points to. ptr may be a
+// pointer to a struct pointer, which is allocated when nil, or a pointer to
+// a struct, which is wired in place.
+// Tagged fields are resolved from the Type map and its providers. Fields
+// whose type is not mapped but is a struct or a pointer to a struct are
+// constructed recursively. PostConstruct is called on every constructed
+// value implementing PostConstructor once its fields are set.
+// Returns an InjectionError if ptr is not a pointer to a struct or if a
+// dependency cannot be resolved, built or is part of a cycle, and a
+// ProviderError if the PostConstruct hook of the struct fails.
+func (inj *injector) Construct(ptr interface{}) error {
+	return inj.construct(ptr, newCall(inj))
+}
+
+// construct allocates and wires the struct ptr points to with values
+// resolved as part of c.
+func (inj *injector) construct(ptr interface{}, c *call) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return &InjectionError{"Construct", "#0", reflect.TypeOf(ptr), errors.New("Construct requires a non-nil pointer")}
+	}
+
+	cons := &construction{
+		inj:      inj,
+		call:     c,
+		built:    make(map[reflect.Type]reflect.Value),
+		visiting: make(map[reflect.Type]bool),
+	}
+
+	e := v.Elem()
+	switch {
+	case e.Kind() == reflect.Ptr && e.Type().Elem().Kind() == reflect.Struct:
+		if e.IsNil() {
+			e.Set(reflect.New(e.Type().Elem()))
+		}
+		return cons.wire(e)
+	case e.Kind() == reflect.Struct:
+		return cons.wire(v)
+	}
+
+	return &InjectionError{"Construct", "#0", v.Type(), errors.New("Construct requires a pointer to a struct")}
+}
+
+// ConstructWithContext works like Construct, resolving the values carried
+// by ctx, see WithValues, before the bindings of the injector. Providers
+// building dependencies receive ctx and are not called anymore once ctx is
+// done: Construct then returns an AbortedError reporting the progress made,
+// so that a startup hanging past its deadline can be diagnosed.
+func (inj *injector) ConstructWithContext(ctx context.Context, ptr interface{}) error {
+	return inj.construct(ptr, newCall(inj).within(ctx))
+}
+
+// wire injects every tagged field of the struct ptr points to and runs its
+// PostConstruct hook.
+func (c *construction) wire(ptr reflect.Value) error {
+	t := ptr.Type()
+	if c.visiting[t] {
+		return &CycleError{t}
+	}
+	c.visiting[t] = true
+	defer delete(c.visiting, t)
+
+	v := ptr.Elem()
+	st := v.Type()
+	fields := c.inj.injectable(st)
+	for k := range fields {
+		sf := &fields[k]
+		f, ok := settable(v, sf.Index)
+		if !ok {
+			continue
+		}
+
+		start := c.inj.started()
+		prev := c.call.consumer
+		c.call.consumer = consumer{owner: st, field: sf.Name}
+		var val reflect.Value
+		err := c.inj.enforce(c.call)
+		if err == nil {
+			val, err = c.inj.field(sf, c.call, c.dependency)
+		}
+		c.call.consumer = prev
+		c.inj.remember(st.String, f.Type(), start, err)
+		if err != nil {
+			return &InjectionError{st.String(), sf.Name, f.Type(), err}
+		}
+		f.Set(val)
+		c.call.advance(st, sf.Name)
+	}
+
+	if pc, ok := ptr.Interface().(PostConstructor); ok {
+		if err := pc.PostConstruct(); err != nil {
+			return &ProviderError{t, fmt.Errorf("PostConstruct failed: %w", err)}
+		}
+	}
+
+	return nil
+}
+
+// dependency resolves t from the injector, falling back to constructing it
+// when t is a struct or a pointer to a struct that has not been mapped.
+func (c *construction) dependency(t reflect.Type) (reflect.Value, error) {
+	val, err := c.inj.lookup(t, c.call)
+	if !missing(err) {
+		return val, err
+	}
+
+	var ptrType reflect.Type
+	switch {
+	case t.Kind() == reflect.Struct:
+		ptrType = reflect.PtrTo(t)
+	case t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct:
+		ptrType = t
+	default:
+		return reflect.Value{}, err
+	}
+
+	ptr, ok := c.built[ptrType]
+	if !ok {
+		ptr = reflect.New(ptrType.Elem())
+		if err := c.wire(ptr); err != nil {
+			return reflect.Value{}, err
+		}
+		c.built[ptrType] = ptr
+	}
+
+	if t.Kind() == reflect.Struct {
+		return ptr.Elem(), nil
+	}
+	return ptr, nil
+}
@@ -0,0 +1,116 @@
+package inject_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type Config struct {
+	Name string
+}
+
+type Repository struct {
+	Config *Config `inject`
+}
+
+type Service struct {
+	Repo   *Repository `inject`
+	Config *Config     `inject`
+	Dep    string      `inject`
+	ready  bool
+}
+
+func (s *Service) PostConstruct() error {
+	s.ready = s.Repo != nil && s.Dep != ""
+	return nil
+}
+
+type Failing struct{}
+
+func (f *Failing) PostConstruct() error {
+	return errors.New("boom")
+}
+
+type Cyclic struct {
+	Other *CyclicOther `inject`
+}
+
+type CyclicOther struct {
+	Back *Cyclic `inject`
+}
+
+func Test_InjectorConstruct(t *testing.T) {
+	injector := inject.New()
+	injector.Map("a dep")
+	injector.Provide(func() *Config { return &Config{Name: "provided"} })
+
+	var s *Service
+	err := injector.Construct(&s)
+	expect(t, err, nil)
+	refute(t, s, (*Service)(nil))
+	expect(t, s.Dep, "a dep")
+	expect(t, s.Config.Name, "provided")
+	expect(t, s.Repo.Config, s.Config)
+	expect(t, s.ready, true)
+}
+
+func Test_InjectorConstructErrors(t *testing.T) {
+	injector := inject.New()
+
+	expect(t, injector.Construct(nil) == nil, false)
+	expect(t, injector.Construct(new(string)) == nil, false)
+
+	var f *Failing
+	expect(t, injector.Construct(&f) == nil, false)
+
+	var c *Cyclic
+	expect(t, injector.Construct(&c) == nil, false)
+
+	var s Service
+	expect(t, injector.Construct(&s) == nil, false)
+}
+
+func Test_InjectorProvide(t *testing.T) {
+	injector := inject.New()
+	calls := 0
+	injector.Map("a dep")
+	injector.Provide(func(dep string) (*Config, error) {
+		calls++
+		return &Config{Name: dep}, nil
+	})
+
+	c1 := injector.Get(reflect.TypeOf((*Config)(nil)))
+	c2 := injector.Get(reflect.TypeOf((*Config)(nil)))
+	expect(t, c1.Interface().(*Config).Name, "a dep")
+	expect(t, c1.Interface(), c2.Interface())
+	expect(t, calls, 1)
+
+	injector.Provide(func() (int, error) { return 0, errors.New("boom") })
+	_, err := injector.Invoke(func(int) {})
+	expect(t, err == nil, false)
+}
+
+func Test_InjectorInvalidate(t *testing.T) {
+	injector := inject.New()
+	calls := 0
+	injector.Provide(func() *Config {
+		calls++
+		return &Config{}
+	})
+	configType := reflect.TypeOf((*Config)(nil))
+
+	c1 := injector.Get(configType)
+	expect(t, injector.Invalidate(configType), nil)
+	c2 := injector.Get(configType)
+	expect(t, calls, 2)
+	expect(t, c1.Interface() == c2.Interface(), false)
+
+	injector.Provide(func() int { return 1 }, inject.Transient())
+	injector.Map("mapped")
+	refute(t, injector.Invalidate(reflect.TypeOf(0)), nil)
+	refute(t, injector.Invalidate(reflect.TypeOf("")), nil)
+	refute(t, injector.Invalidate(reflect.TypeOf(0.5)), nil)
+}
@@ -0,0 +1,61 @@
+package inject
+
+import (
+	"context"
+	"reflect"
+)
+
+type (
+	valuesKey   struct{}
+	injectorKey struct{}
+)
+
+// NewContext returns a copy of ctx carrying inj, so that code only
+// receiving a context can reach the active injector with FromContext.
+func NewContext(ctx context.Context, inj Injector) context.Context {
+	return context.WithValue(ctx, injectorKey{}, inj)
+}
+
+// FromContext returns the injector carried by ctx, if any.
+func FromContext(ctx context.Context) (Injector, bool) {
+	inj, ok := ctx.Value(injectorKey{}).(Injector)
+	return inj, ok
+}
+
+// WithValues returns a copy of ctx carrying vals, each mapped to its
+// dynamic type as Map does. Values added by an outer call to WithValues are
+// kept unless one of vals has the same type.
+// Values carried this way are resolved by InvokeWithContext and
+// ApplyWithContext before the bindings of the injector.
+func WithValues(ctx context.Context, vals ...interface{}) context.Context {
+	prev, _ := ctx.Value(valuesKey{}).(map[reflect.Type]reflect.Value)
+	values := make(map[reflect.Type]reflect.Value, len(prev)+len(vals))
+	for t, v := range prev {
+		values[t] = v
+	}
+	for _, val := range vals {
+		values[reflect.TypeOf(val)] = reflect.ValueOf(val)
+	}
+	return context.WithValue(ctx, valuesKey{}, values)
+}
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// InvokeWithContext calls f like Invoke, resolving its arguments from the
+// values carried by ctx, see WithValues, before the bindings of the
+// injector. ctx itself is provided for arguments of type context.Context,
+// including those of the providers built along the way, and the
+// construction of a provider is aborted once ctx is done. Arguments of
+// type context.Context resolved outside of such a call receive
+// context.Background unless a context is mapped.
+func (inj *injector) InvokeWithContext(ctx context.Context, f interface{}) ([]reflect.Value, error) {
+	return inj.invoke(f, newCall(inj).within(ctx), nil)
+}
+
+// ApplyWithContext injects the tagged fields of val like Apply, resolving
+// them from the values carried by ctx before the bindings of the injector.
+// Once ctx is done, providers are not called anymore and the AbortedError
+// returned reports the fields set and the values built so far.
+func (inj *injector) ApplyWithContext(ctx context.Context, val interface{}) error {
+	return inj.apply(val, newCall(inj).within(ctx))
+}
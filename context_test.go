@@ -0,0 +1,89 @@
+package inject_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorInvokeWithContext(t *testing.T) {
+	injector := inject.New()
+	injector.Map("app dep").Map(1)
+
+	ctx := inject.WithValues(context.Background(), "request dep")
+	ctx = inject.WithValues(ctx, 2)
+
+	_, err := injector.InvokeWithContext(ctx, func(c context.Context, s string, n int) {
+		expect(t, c, ctx)
+		expect(t, s, "request dep")
+		expect(t, n, 2)
+	})
+	expect(t, err, nil)
+
+	_, err = injector.Invoke(func(s string) {
+		expect(t, s, "app dep")
+	})
+	expect(t, err, nil)
+
+	s := struct {
+		Dep string `inject`
+	}{}
+	expect(t, injector.ApplyWithContext(ctx, &s), nil)
+	expect(t, s.Dep, "request dep")
+}
+
+func Test_InjectorInvokeWithContextInjector(t *testing.T) {
+	injector := inject.New()
+	injector.SetScope("app")
+
+	ctx := inject.WithValues(context.Background(), "request dep")
+	_, err := injector.InvokeWithContext(ctx, func(inj inject.Injector) {
+		expect(t, inj, injector)
+		// the values of the context are those of the call only
+		expect(t, inj.Get(reflect.TypeOf("")).IsValid(), false)
+	})
+	expect(t, err, nil)
+}
+
+func Test_InjectorFromContext(t *testing.T) {
+	injector := inject.New()
+
+	_, ok := inject.FromContext(context.Background())
+	expect(t, ok, false)
+
+	inj, ok := inject.FromContext(inject.NewContext(context.Background(), injector))
+	expect(t, ok, true)
+	expect(t, inj, injector)
+}
+
+type slowStartup struct {
+	Config *Config `inject`
+	Rate   float64 `inject`
+	Name   string  `inject`
+}
+
+func Test_InjectorConstructWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	injector := inject.New()
+	injector.Map("name")
+	injector.Provide(func(ctx context.Context) *Config {
+		cancel()
+		return &Config{}
+	})
+	injector.Provide(func() float64 { return 1 })
+
+	var s *slowStartup
+	err := injector.ConstructWithContext(ctx, &s)
+	var aborted *inject.AbortedError
+	expect(t, errors.As(err, &aborted), true)
+	expect(t, errors.Is(err, context.Canceled), true)
+	expect(t, aborted.Type, reflect.TypeOf(1.5))
+	expect(t, strings.Join(aborted.Progress, ", "), "built *inject_test.Config, set inject_test.slowStartup.Config")
+	expect(t, strings.Contains(err.Error(), "(after built *inject_test.Config"), true)
+
+	expect(t, injector.ApplyWithContext(context.Background(), &slowStartup{}), nil)
+}
@@ -0,0 +1,108 @@
+package inject
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Conversions returns an Option resolving a type that is not bound from a
+// bound value convertible to it, e.g. an int64 binding into an int
+// argument or a MyString binding into a string field. Only numbers are
+// converted into numbers, strings into strings and values into types of
+// the same kind; a conversion that overflows or loses precision fails
+// the resolution. Bindings registered with the Strict option are never
+// converted, and a type convertible from several bound types is reported
+// as not found.
+func Conversions() Option {
+	return func(i *injector) {
+		i.conversions = true
+	}
+}
+
+// Strict returns a BindOption excluding the binding from the conversions
+// enabled by the Conversions option.
+func Strict() BindOption {
+	return func(e *binding) {
+		e.strict = true
+	}
+}
+
+// convert resolves t from the only bound type of the injector and its
+// parents that can be converted to t.
+func (i *injector) convert(t reflect.Type, c *call) (reflect.Value, error) {
+	var from reflect.Type
+	for _, cand := range i.mappedTypes() {
+		if cand.strict || cand.typ == from || !convertible(cand.typ, t) {
+			continue
+		}
+		if from != nil {
+			return reflect.Value{}, ErrNotFound
+		}
+		from = cand.typ
+	}
+	if from == nil {
+		return reflect.Value{}, ErrNotFound
+	}
+
+	val, err := i.lookup(from, c)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if isNumber(t.Kind()) && !lossless(val, t) {
+		return reflect.Value{}, fmt.Errorf("Cannot convert %v of type %v to %v without loss", val, from, t)
+	}
+	return val.Convert(t), nil
+}
+
+// lossless reports whether the number val converts to the number type t
+// without overflowing, flipping its sign or losing precision.
+func lossless(val reflect.Value, t reflect.Type) bool {
+	k := t.Kind()
+	switch {
+	case val.CanInt():
+		n := val.Int()
+		switch {
+		case k >= reflect.Int && k <= reflect.Int64:
+			return !t.OverflowInt(n)
+		case k >= reflect.Uint && k <= reflect.Uintptr:
+			return n >= 0 && !t.OverflowUint(uint64(n))
+		}
+	case val.CanUint():
+		n := val.Uint()
+		switch {
+		case k >= reflect.Int && k <= reflect.Int64:
+			return n <= math.MaxInt64 && !t.OverflowInt(int64(n))
+		case k >= reflect.Uint && k <= reflect.Uintptr:
+			return !t.OverflowUint(n)
+		}
+	default:
+		f := val.Float()
+		switch {
+		case k >= reflect.Int && k <= reflect.Int64:
+			return f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 && !t.OverflowInt(int64(f))
+		case k >= reflect.Uint && k <= reflect.Uintptr:
+			return f == math.Trunc(f) && f >= 0 && f < math.MaxUint64 && !t.OverflowUint(uint64(f))
+		}
+		if math.IsNaN(f) {
+			return true
+		}
+	}
+	// the remaining conversions are to floats, which only lose precision
+	return val.Convert(t).Convert(val.Type()).Equal(val)
+}
+
+// convertible reports whether values of type from may be converted to t.
+func convertible(from, t reflect.Type) bool {
+	if !from.ConvertibleTo(t) {
+		return false
+	}
+	if isNumber(from.Kind()) {
+		return isNumber(t.Kind())
+	}
+	return from.Kind() == t.Kind() && t.Kind() != reflect.Interface
+}
+
+func isNumber(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
@@ -0,0 +1,78 @@
+package inject_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type MyString string
+
+func Test_InjectorConversions(t *testing.T) {
+	injector := inject.New(inject.Conversions())
+	injector.Map(int64(42)).Map(MyString("hello"))
+
+	_, err := injector.Invoke(func(n int, f float64, s string) {
+		expect(t, n, 42)
+		expect(t, f, 42.0)
+		expect(t, s, "hello")
+	})
+	expect(t, err, nil)
+
+	strict := inject.New()
+	strict.Map(int64(42))
+	_, err = strict.Invoke(func(int) {})
+	refute(t, err, nil)
+}
+
+func Test_InjectorConversionsLoss(t *testing.T) {
+	injector := inject.New(inject.Conversions())
+	injector.Map(int64(math.MaxInt64))
+	_, err := injector.Invoke(func(int8) {})
+	refute(t, err, nil)
+
+	injector = inject.New(inject.Conversions())
+	injector.Map(1.5)
+	_, err = injector.Invoke(func(int) {})
+	refute(t, err, nil)
+}
+
+func Test_InjectorConversionsSign(t *testing.T) {
+	injector := inject.New(inject.Conversions())
+	injector.Map(int64(-1))
+	_, err := injector.Invoke(func(uint64) {})
+	refute(t, err, nil)
+	_, err = injector.Invoke(func(uint8) {})
+	refute(t, err, nil)
+
+	injector = inject.New(inject.Conversions())
+	injector.Map(uint64(math.MaxUint64))
+	_, err = injector.Invoke(func(int64) {})
+	refute(t, err, nil)
+
+	injector = inject.New(inject.Conversions())
+	injector.Map(-2.0)
+	_, err = injector.Invoke(func(uint) {})
+	refute(t, err, nil)
+
+	injector = inject.New(inject.Conversions())
+	injector.Map(uint8(200))
+	_, err = injector.Invoke(func(n int64, f float32) {
+		expect(t, n, int64(200))
+		expect(t, f, float32(200))
+	})
+	expect(t, err, nil)
+}
+
+func Test_InjectorConversionsStrict(t *testing.T) {
+	injector := inject.New(inject.Conversions())
+	injector.MapWith(int64(1), inject.Strict())
+	_, err := injector.Invoke(func(int) {})
+	refute(t, err, nil)
+
+	injector = inject.New(inject.Conversions())
+	injector.Map(int64(1)).Map(int32(2))
+	_, err = injector.Invoke(func(int) {})
+	refute(t, err, nil)
+}
@@ -0,0 +1,120 @@
+// Package ctxinject is a context-first API over the injector of
+// github.com/codegangsta/inject. Every operation that may build a value,
+// from Invoke to Dispose, takes the context.Context it runs under first, so
+// that deadlines and cancellation reach the providers, which receive the
+// context as any argument of type context.Context. An Injector wraps an
+// injector of package inject, see Adapt, so that both APIs can coexist
+// while a code base migrates: bindings made through either are seen by
+// both.
+package ctxinject
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	v1 "github.com/codegangsta/inject"
+)
+
+// Injector maps and injects dependencies into functions and structs.
+//
+// Errors are structured: a dependency that cannot be injected is reported
+// as a *v1.InjectionError, whose chain holds the v1.ProviderError,
+// v1.CycleError, v1.AbortedError or v1.NotFoundError at its root, so that
+// callers match them with errors.As and errors.Is.
+type Injector interface {
+	v1.TypeMapper
+	// Invoke calls f with injected arguments. Returns the values f
+	// returned and, when its last result is a non-nil error, that error.
+	Invoke(ctx context.Context, f interface{}) ([]reflect.Value, error)
+	// Apply injects the tagged fields of the struct val points to.
+	Apply(ctx context.Context, val interface{}) error
+	// Construct allocates and wires the struct ptr points to, see
+	// v1.Injector.Construct.
+	Construct(ctx context.Context, ptr interface{}) error
+	// Warm builds the values of every memoized provider. Returns an error
+	// if the wrapped injector does not implement v1.Warmer.
+	Warm(ctx context.Context, opts ...v1.WarmOption) error
+	// Dispose releases the values built by the providers of the injector
+	// and the functions registered with OnDispose. Returns the error of
+	// ctx if it is done before the release completed, which goes on in
+	// the background.
+	Dispose(ctx context.Context) error
+	// OnDispose registers a function to be called by Dispose.
+	OnDispose(func() error)
+	// Child returns a new injector whose parent is the injector.
+	Child(opts ...v1.Option) Injector
+	// V1 returns the injector of package inject the Injector wraps.
+	V1() v1.Injector
+}
+
+// New returns a new Injector configured by opts.
+func New(opts ...v1.Option) Injector {
+	return Adapt(v1.New(opts...))
+}
+
+// Adapt returns an Injector wrapping inj, for code written against
+// package inject to hand its injector to code written against this one.
+// Use V1 for the reverse direction.
+func Adapt(inj v1.Injector) Injector {
+	return &adapter{inj, inj}
+}
+
+type adapter struct {
+	v1.TypeMapper
+	inj v1.Injector
+}
+
+func (a *adapter) Invoke(ctx context.Context, f interface{}) ([]reflect.Value, error) {
+	out, err := a.inj.InvokeWithContext(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	if n := len(out); n > 0 && out[n-1].Type() == errorType && !out[n-1].IsNil() {
+		return out, out[n-1].Interface().(error)
+	}
+	return out, nil
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+func (a *adapter) Apply(ctx context.Context, val interface{}) error {
+	return a.inj.ApplyWithContext(ctx, val)
+}
+
+func (a *adapter) Construct(ctx context.Context, ptr interface{}) error {
+	return a.inj.ConstructWithContext(ctx, ptr)
+}
+
+func (a *adapter) Warm(ctx context.Context, opts ...v1.WarmOption) error {
+	w, ok := a.inj.(v1.Warmer)
+	if !ok {
+		return fmt.Errorf("Injector %T cannot warm its providers", a.inj)
+	}
+	return w.Warm(ctx, opts...)
+}
+
+func (a *adapter) Dispose(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- a.inj.Dispose()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (a *adapter) OnDispose(fn func() error) {
+	a.inj.OnDispose(fn)
+}
+
+func (a *adapter) Child(opts ...v1.Option) Injector {
+	return Adapt(a.inj.Child(opts...))
+}
+
+func (a *adapter) V1() v1.Injector {
+	return a.inj
+}
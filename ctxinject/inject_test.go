@@ -0,0 +1,77 @@
+package ctxinject_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v1 "github.com/codegangsta/inject"
+	"github.com/codegangsta/inject/ctxinject"
+)
+
+func expect(t *testing.T, a interface{}, b interface{}) {
+	t.Helper()
+	if a != b {
+		t.Errorf("Expected %v (type %T) - Got %v (type %T)", b, b, a, a)
+	}
+}
+
+type key string
+
+type service struct {
+	Name string `inject`
+}
+
+func Test_Injector(t *testing.T) {
+	injector := ctxinject.New()
+	injector.Provide(func(ctx context.Context) string {
+		return string(ctx.Value(key("user")).(string))
+	}, v1.Transient())
+
+	ctx := context.WithValue(context.Background(), key("user"), "gopher")
+	out, err := injector.Invoke(ctx, func(s string) string { return s })
+	expect(t, err, nil)
+	expect(t, out[0].String(), "gopher")
+
+	var s service
+	expect(t, injector.Apply(ctx, &s), nil)
+	expect(t, s.Name, "gopher")
+
+	boom := errors.New("boom")
+	_, err = injector.Invoke(ctx, func() (int, error) { return 0, boom })
+	expect(t, err, boom)
+
+	_, err = injector.Invoke(ctx, func(int) {})
+	var ie *v1.InjectionError
+	expect(t, errors.As(err, &ie), true)
+	expect(t, errors.Is(err, v1.ErrNotFound), true)
+}
+
+func Test_Adapt(t *testing.T) {
+	old := v1.New()
+	old.Map(42)
+	injector := ctxinject.Adapt(old)
+	injector.Map("shared")
+	expect(t, injector.V1(), old)
+
+	_, err := old.Invoke(func(s string) { expect(t, s, "shared") })
+	expect(t, err, nil)
+	child := injector.Child()
+	_, err = child.Invoke(context.Background(), func(n int) { expect(t, n, 42) })
+	expect(t, err, nil)
+}
+
+func Test_InjectorDispose(t *testing.T) {
+	injector := ctxinject.New()
+	release := make(chan struct{})
+	injector.OnDispose(func() error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	expect(t, injector.Dispose(ctx), context.DeadlineExceeded)
+	close(release)
+}
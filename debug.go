@@ -0,0 +1,252 @@
+package inject
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+)
+
+// DebugHandler returns an http.Handler serving the state of inj as JSON,
+// meant to be mounted under /debug/inject for the live inspection of the
+// wiring of a running program. The last element of the request path
+// selects what is served:
+//
+//	scopes    the scopes of inj and of its parents, depth first
+//	bindings  the bindings of inj and of its parents, see ForEach
+//	stats     the resolution statistics of inj, see Statistics
+//	graph     the dependency graph of inj, see Graph
+//	metrics   the metrics of the dependency graph, see Graph.Stats
+//
+// Any other path serves all of them in a single document. The handler
+// exposes type names and source locations, which should not be served to
+// untrusted clients. The mutation endpoints enabled by AllowMutations are
+// served to POST requests.
+func DebugHandler(inj Injector, opts ...DebugOption) http.Handler {
+	h := &debugHandler{inj: inj}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// DebugOption configures the handler returned by DebugHandler.
+type DebugOption func(*debugHandler)
+
+// AllowMutations returns a DebugOption enabling the endpoints of the debug
+// handler reconfiguring the injector at run time. They are served to POST
+// requests that authorize accepts, which typically checks a token or a
+// client certificate, and answer 403 Forbidden to the others:
+//
+//	activate?profile=name  activates the named profile, see Activate,
+//	                       swapping the bindings it sets up to the
+//	                       alternatives it registers
+//	invalidate?type=name   invalidates the memoized provider of the type
+//	                       with the given name, see Invalidate
+//
+// Successful mutations answer 204 No Content.
+// It panics if authorize is nil.
+func AllowMutations(authorize func(*http.Request) bool) DebugOption {
+	if authorize == nil {
+		panic("Called inject.AllowMutations with a nil authorization function")
+	}
+	return func(h *debugHandler) {
+		h.authorize = authorize
+	}
+}
+
+type debugHandler struct {
+	inj       Injector
+	authorize func(*http.Request) bool
+}
+
+type debugScope struct {
+	Scope Scope `json:"scope"`
+	Depth int   `json:"depth"`
+}
+
+type debugBinding struct {
+	Type     string `json:"type"`
+	Name     string `json:"name,omitempty"`
+	Scope    Scope  `json:"scope,omitempty"`
+	Depth    int    `json:"depth"`
+	Source   string `json:"source,omitempty"`
+	Provided bool   `json:"provided"`
+}
+
+type debugNode struct {
+	Type         string   `json:"type"`
+	Name         string   `json:"name,omitempty"`
+	Depth        int      `json:"depth"`
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+type debugMetrics struct {
+	MaxDepth int            `json:"maxDepth"`
+	FanIn    map[string]int `json:"fanIn"`
+	FanOut   map[string]int `json:"fanOut"`
+	Orphans  []string       `json:"orphans"`
+	Cycles   [][]string     `json:"cycles"`
+}
+
+type debugState struct {
+	Scopes   []debugScope    `json:"scopes"`
+	Bindings []debugBinding  `json:"bindings"`
+	Stats    map[string]Stat `json:"stats"`
+	Graph    []debugNode     `json:"graph"`
+	Metrics  debugMetrics    `json:"metrics"`
+}
+
+func (h *debugHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && h.authorize != nil:
+		h.mutate(w, r)
+		return
+	case r.Method != http.MethodGet && r.Method != http.MethodHead:
+		allow := "GET, HEAD"
+		if h.authorize != nil {
+			allow += ", POST"
+		}
+		w.Header().Set("Allow", allow)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var v interface{}
+	switch path.Base(r.URL.Path) {
+	case "scopes":
+		v = h.scopes()
+	case "bindings":
+		v = h.bindings()
+	case "stats":
+		v = statsByName(h.inj)
+	case "graph":
+		v = h.graph()
+	case "metrics":
+		v = h.metrics()
+	default:
+		v = debugState{h.scopes(), h.bindings(), statsByName(h.inj), h.graph(), h.metrics()}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// mutate serves the endpoints enabled by AllowMutations.
+func (h *debugHandler) mutate(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	var err error
+	switch path.Base(r.URL.Path) {
+	case "activate":
+		err = h.inj.Activate(r.FormValue("profile"))
+	case "invalidate":
+		err = h.invalidate(r.FormValue("type"))
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// invalidate invalidates the provider of the type of the injector named
+// name.
+func (h *debugHandler) invalidate(name string) error {
+	var t reflect.Type
+	h.inj.ForEach(func(b Binding) bool {
+		if b.Depth == 0 && b.Name == "" && b.Type.String() == name {
+			t = b.Type
+		}
+		return t == nil
+	})
+	if t == nil {
+		return fmt.Errorf("Type %s is not bound in the injector", name)
+	}
+	return h.inj.Invalidate(t)
+}
+
+func (h *debugHandler) scopes() []debugScope {
+	i, ok := h.inj.(*injector)
+	if !ok {
+		return []debugScope{{h.inj.Scope(), 0}}
+	}
+	var scopes []debugScope
+	var walk func(*injector, int)
+	walk = func(i *injector, depth int) {
+		b := i.snapshot()
+		scopes = append(scopes, debugScope{b.scope, depth})
+		for _, p := range b.parents {
+			if pi, ok := p.inj.(*injector); ok {
+				walk(pi, depth+1)
+			}
+		}
+	}
+	walk(i, 0)
+	return scopes
+}
+
+func (h *debugHandler) bindings() []debugBinding {
+	bindings := []debugBinding{}
+	h.inj.ForEach(func(b Binding) bool {
+		bindings = append(bindings, debugBinding{b.Type.String(), b.Name, b.Scope, b.Depth, b.Source, b.Provided})
+		return true
+	})
+	return bindings
+}
+
+// graphOf returns the Graph of the injector, an empty one if it does not
+// implement Diagnostics.
+func (h *debugHandler) graphOf() Graph {
+	if d, ok := h.inj.(Diagnostics); ok {
+		return d.Graph()
+	}
+	return Graph{}
+}
+
+func (h *debugHandler) graph() []debugNode {
+	nodes := []debugNode{}
+	for _, n := range h.graphOf().Nodes {
+		nodes = append(nodes, debugNode{n.Type.String(), n.Name, n.Depth, typeNames(n.Dependencies)})
+	}
+	return nodes
+}
+
+func (h *debugHandler) metrics() debugMetrics {
+	s := h.graphOf().Stats()
+	m := debugMetrics{
+		MaxDepth: s.MaxDepth,
+		FanIn:    make(map[string]int),
+		FanOut:   make(map[string]int),
+		Orphans:  typeNames(s.Orphans),
+		Cycles:   [][]string{},
+	}
+	for t, n := range s.FanIn {
+		m.FanIn[t.String()] = n
+	}
+	for t, n := range s.FanOut {
+		m.FanOut[t.String()] = n
+	}
+	for _, c := range s.Cycles {
+		m.Cycles = append(m.Cycles, typeNames(c))
+	}
+	return m
+}
+
+// typeNames returns the names of types.
+func typeNames(types []reflect.Type) []string {
+	var names []string
+	for _, t := range types {
+		names = append(names, t.String())
+	}
+	return names
+}
@@ -0,0 +1,116 @@
+package inject_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_DebugHandler(t *testing.T) {
+	parent := inject.New(inject.Statistics())
+	parent.SetScope(inject.Singleton)
+	parent.Map("dsn")
+	injector := parent.Child()
+	injector.SetScope(inject.Request)
+	injector.Provide(func(dsn string) *Config { return &Config{} })
+	injector.Invoke(func(*Config) {})
+
+	h := inject.DebugHandler(injector)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/inject/", nil))
+	expect(t, rec.Code, http.StatusOK)
+	expect(t, rec.Header().Get("Content-Type"), "application/json")
+
+	var state struct {
+		Scopes []struct {
+			Scope string
+			Depth int
+		}
+		Bindings []struct {
+			Type     string
+			Provided bool
+		}
+		Stats map[string]struct{ Count int }
+		Graph []struct {
+			Type         string
+			Dependencies []string
+		}
+	}
+	expect(t, json.Unmarshal(rec.Body.Bytes(), &state), nil)
+	expect(t, len(state.Scopes), 2)
+	expect(t, state.Scopes[1].Scope, "singleton")
+	expect(t, len(state.Bindings), 2)
+	expect(t, state.Bindings[0].Type, "*inject_test.Config")
+	expect(t, state.Bindings[0].Provided, true)
+	expect(t, state.Stats["*inject_test.Config"].Count, 1)
+	expect(t, state.Graph[0].Dependencies[0], "string")
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/inject/stats", nil))
+	var stats map[string]struct{ Count int }
+	expect(t, json.Unmarshal(rec.Body.Bytes(), &stats), nil)
+	expect(t, len(stats), 1)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/inject/metrics", nil))
+	var metrics struct {
+		MaxDepth int
+		FanIn    map[string]int
+		Orphans  []string
+	}
+	expect(t, json.Unmarshal(rec.Body.Bytes(), &metrics), nil)
+	expect(t, metrics.MaxDepth, 1)
+	expect(t, metrics.FanIn["string"], 1)
+	expect(t, len(metrics.Orphans), 1)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("DELETE", "/debug/inject/", nil))
+	expect(t, rec.Code, http.StatusMethodNotAllowed)
+}
+
+func Test_DebugHandlerMutations(t *testing.T) {
+	injector := inject.New()
+	built := 0
+	injector.Provide(func() *Config {
+		built++
+		return &Config{}
+	})
+	injector.Profile("stub", func(m inject.TypeMapper) {
+		m.Map("stub")
+	})
+	injector.Map("real")
+	injector.Invoke(func(*Config) {})
+
+	h := inject.DebugHandler(injector, inject.AllowMutations(func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == "Bearer secret"
+	}))
+	post := func(target string, authorized bool) int {
+		r := httptest.NewRequest("POST", target, nil)
+		if authorized {
+			r.Header.Set("Authorization", "Bearer secret")
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		return rec.Code
+	}
+
+	expect(t, post("/debug/inject/invalidate?type=*inject_test.Config", false), http.StatusForbidden)
+	expect(t, post("/debug/inject/invalidate?type=*inject_test.Config", true), http.StatusNoContent)
+	injector.Invoke(func(*Config) {})
+	expect(t, built, 2)
+	expect(t, post("/debug/inject/invalidate?type=string", true), http.StatusBadRequest)
+	expect(t, post("/debug/inject/invalidate?type=float64", true), http.StatusBadRequest)
+
+	expect(t, post("/debug/inject/activate?profile=stub", true), http.StatusNoContent)
+	expect(t, injector.Get(reflect.TypeOf("")).String(), "stub")
+	expect(t, post("/debug/inject/activate?profile=unknown", true), http.StatusBadRequest)
+	expect(t, post("/debug/inject/unknown", true), http.StatusNotFound)
+
+	rec := httptest.NewRecorder()
+	inject.DebugHandler(injector).ServeHTTP(rec, httptest.NewRequest("POST", "/debug/inject/activate?profile=stub", nil))
+	expect(t, rec.Code, http.StatusMethodNotAllowed)
+}
@@ -0,0 +1,65 @@
+package inject
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// std holds the default injector used by the package level functions.
+var std atomic.Value
+
+func init() {
+	std.Store(injectorBox{New()})
+}
+
+// injectorBox lets std store injectors of different dynamic types.
+type injectorBox struct {
+	inj Injector
+}
+
+// Default returns the default injector used by the package level Map,
+// MapTo, Provide, Invoke, Apply and Populate functions, so that small
+// programs and examples need not thread an Injector through every
+// function. It is safe for concurrent use like any injector.
+func Default() Injector {
+	return std.Load().(injectorBox).inj
+}
+
+// SetDefault replaces the default injector, e.g. with a child of it in a
+// test, and returns the previous one.
+func SetDefault(inj Injector) Injector {
+	return std.Swap(injectorBox{inj}).(injectorBox).inj
+}
+
+// Map maps val in the default injector, see TypeMapper.
+func Map(val interface{}, opts ...BindOption) TypeMapper {
+	return Default().MapWith(val, opts...)
+}
+
+// MapTo maps val as the interface ifacePtr points to in the default
+// injector, see TypeMapper.
+func MapTo(val interface{}, ifacePtr interface{}, opts ...BindOption) TypeMapper {
+	return Default().MapToWith(val, ifacePtr, opts...)
+}
+
+// Provide registers provider in the default injector, see TypeMapper.
+func Provide(provider interface{}, opts ...BindOption) TypeMapper {
+	return Default().Provide(provider, opts...)
+}
+
+// Invoke calls f with arguments resolved from the default injector, see
+// Invoker.
+func Invoke(f interface{}) ([]reflect.Value, error) {
+	return Default().Invoke(f)
+}
+
+// Apply injects the tagged fields of val from the default injector, see
+// Applicator.
+func Apply(val interface{}) error {
+	return Default().Apply(val)
+}
+
+// Populate sets the values ptrs point to from the default injector.
+func Populate(ptrs ...interface{}) error {
+	return Default().Populate(ptrs...)
+}
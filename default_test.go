@@ -0,0 +1,40 @@
+package inject_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_DefaultInjector(t *testing.T) {
+	prev := inject.SetDefault(inject.New())
+	defer inject.SetDefault(prev)
+
+	inject.Map("default")
+	inject.Provide(func(s string) int { return len(s) })
+
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := inject.Invoke(func(s string, n int) {
+				expect(t, s, "default")
+				expect(t, n, 7)
+			})
+			expect(t, err, nil)
+		}()
+	}
+	wg.Wait()
+
+	s := struct {
+		S string `inject`
+	}{}
+	expect(t, inject.Apply(&s), nil)
+	expect(t, s.S, "default")
+
+	var n int
+	expect(t, inject.Populate(&n), nil)
+	expect(t, n, 7)
+}
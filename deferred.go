@@ -0,0 +1,59 @@
+package inject
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// deferred is a binding registered with MapDeferred that is bound once
+// the injector is sealed.
+type deferred struct {
+	typ  reflect.Type
+	fn   func(Injector) (interface{}, error)
+	opts []BindOption
+}
+
+// MapDeferred binds typ to the value fn computes with the injector when
+// the injector is sealed, for values depending on the final shape of the
+// container, such as a router built from every handler registered by the
+// modules of an application. fn is called exactly once, by the first Seal
+// or Validate following the registration; typ is not bound until then.
+func (i *injector) MapDeferred(typ reflect.Type, fn func(Injector) (interface{}, error), opts ...BindOption) TypeMapper {
+	i.mu.Lock()
+	i.deferred = append(i.deferred, deferred{typ, fn, opts})
+	i.mu.Unlock()
+	return i
+}
+
+// Seal computes the values of the bindings registered with MapDeferred
+// since the injector was last sealed and binds them, in the order they
+// were registered, so that each function sees the values computed before
+// it. Seal is called by Validate.
+// Returns an error joining the failures of the functions and the values
+// that are not assignable to their type, whose types are left unbound.
+func (i *injector) Seal() error {
+	i.mu.Lock()
+	pending := i.deferred
+	i.deferred = nil
+	i.mu.Unlock()
+
+	var errs []error
+	for _, d := range pending {
+		v, err := d.fn(i)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Deferred binding for type %v failed: %w", d.typ, err))
+			continue
+		}
+		val := reflect.ValueOf(v)
+		if !val.IsValid() || !val.Type().AssignableTo(d.typ) {
+			errs = append(errs, fmt.Errorf("Deferred binding for type %v returned a %T", d.typ, v))
+			continue
+		}
+		if val.Type() != d.typ {
+			val = val.Convert(d.typ)
+		}
+		i.SetWith(d.typ, val, d.opts...)
+	}
+	return errors.Join(errs...)
+}
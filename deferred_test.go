@@ -0,0 +1,47 @@
+package inject_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type route string
+
+func Test_InjectorMapDeferred(t *testing.T) {
+	injector := inject.New()
+	routes := reflect.TypeOf([]route(nil))
+	stringer := reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	calls := 0
+	injector.MapDeferred(routes, func(inj inject.Injector) (interface{}, error) {
+		calls++
+		var all []route
+		for _, v := range inj.GetAll(reflect.TypeOf(route(""))) {
+			all = append(all, v.Interface().(route))
+		}
+		return all, nil
+	})
+	injector.Map(route("/users"))
+	expect(t, injector.Get(routes).IsValid(), false)
+
+	expect(t, injector.Validate(func([]route) {}), nil)
+	expect(t, calls, 1)
+	expect(t, fmt.Sprint(injector.Get(routes).Interface()), "[/users]")
+	expect(t, injector.Seal(), nil)
+	expect(t, calls, 1)
+
+	boom := errors.New("boom")
+	injector.MapDeferred(stringer, func(inject.Injector) (interface{}, error) {
+		return nil, boom
+	})
+	injector.MapDeferred(stringer, func(inject.Injector) (interface{}, error) {
+		return 42, nil
+	})
+	err := injector.Seal()
+	expect(t, errors.Is(err, boom), true)
+	expect(t, err.Error(), "Deferred binding for type fmt.Stringer failed: boom\nDeferred binding for type fmt.Stringer returned a int")
+	expect(t, injector.Get(stringer).IsValid(), false)
+}
@@ -0,0 +1,38 @@
+package inject
+
+import "reflect"
+
+// DelegationPolicy controls when an injector asks its parent for a type.
+type DelegationPolicy int
+
+const (
+	// ChildFirst resolves types from the injector itself and asks the
+	// parent only for missing types. This is the default.
+	ChildFirst DelegationPolicy = iota
+	// ParentFirst asks the parent first and falls back to the injector's
+	// own bindings for types the parent cannot resolve.
+	ParentFirst
+	// Whitelist resolves types from the injector itself and asks the parent
+	// only for the whitelisted types, which keeps sandboxed injectors from
+	// seeing arbitrary bindings of their host.
+	Whitelist
+)
+
+// Delegation returns an Option setting the delegation policy of the
+// injector. types lists the types the parent may be asked for when policy
+// is Whitelist and is ignored otherwise. Children do not inherit the
+// policy: a Child of a sandbox resolves the bindings of the sandbox.
+func Delegation(policy DelegationPolicy, types ...reflect.Type) Option {
+	return func(i *injector) {
+		i.delegation = policy
+		i.whitelist = make(map[reflect.Type]bool, len(types))
+		for _, t := range types {
+			i.whitelist[t] = true
+		}
+	}
+}
+
+// delegates reports whether the parent may be asked for t.
+func (i *injector) delegates(t reflect.Type) bool {
+	return i.delegation != Whitelist || i.whitelist[t]
+}
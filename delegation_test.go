@@ -0,0 +1,50 @@
+package inject_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorDelegation(t *testing.T) {
+	parent := inject.New()
+	parent.Map("parent dep").Map(42)
+
+	typ := reflect.TypeOf("")
+	childFirst := inject.New()
+	childFirst.SetParent(parent)
+	childFirst.Map("child dep")
+	expect(t, childFirst.Get(typ).String(), "child dep")
+
+	parentFirst := inject.New(inject.Delegation(inject.ParentFirst))
+	parentFirst.SetParent(parent)
+	parentFirst.Map("child dep").Map(1.5)
+	expect(t, parentFirst.Get(typ).String(), "parent dep")
+	expect(t, parentFirst.Get(reflect.TypeOf(1.5)).IsValid(), true)
+
+	sandbox := inject.New(inject.Delegation(inject.Whitelist, typ))
+	sandbox.SetParent(parent)
+	expect(t, sandbox.Get(typ).String(), "parent dep")
+	expect(t, sandbox.Get(reflect.TypeOf(42)).IsValid(), false)
+	refute(t, sandbox.Validate(func(int) {}), nil)
+	expect(t, sandbox.Validate(func(string) {}), nil)
+}
+
+func Test_InjectorDelegationNotInherited(t *testing.T) {
+	host := inject.New()
+	host.Map("host dep").Map(42)
+
+	typ := reflect.TypeOf(42)
+	sandbox := host.Child(inject.Delegation(inject.Whitelist))
+	sandbox.Map(1.5)
+	request := sandbox.Child()
+	expect(t, request.Get(reflect.TypeOf(1.5)).Float(), 1.5)
+	expect(t, request.Get(typ).IsValid(), false)
+
+	parentFirst := host.Child(inject.Delegation(inject.ParentFirst))
+	override := parentFirst.Child()
+	override.Map(7)
+	expect(t, override.Get(typ).Int(), int64(7))
+	expect(t, parentFirst.Get(typ).Int(), int64(42))
+}
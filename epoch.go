@@ -0,0 +1,271 @@
+package inject
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// bindings is a snapshot of the Type map of an injector. A published
+// snapshot is never modified: writers publish a modified copy instead, so
+// that concurrent readers never see a half-updated map. The copy shares
+// the unmodified parts of the snapshot it is cloned from: entries is a
+// persistent trie, and the maps are copied the first time they are
+// written, see own.
+type bindings struct {
+	entries typeTrie
+	// named holds the bindings registered with the Named option.
+	named map[namedKey]*binding
+	// groups holds the members of every group, see Group, in the order
+	// they were registered.
+	groups map[string][]member
+	// implementers holds, for every interface type an implementation has
+	// been looked up for, the types of entries implementing it, by name.
+	implementers map[reflect.Type][]reflect.Type
+	// parents and scope are those set with SetParent, AddParent and
+	// SetScope.
+	parents []parentLink
+	scope   Scope
+	// owned flags the maps of a snapshot being modified that have been
+	// copied from the snapshot it was cloned from.
+	owned uint8
+}
+
+// The maps of bindings flagged by owned.
+const (
+	ownsNamed uint8 = 1 << iota
+	ownsGroups
+	ownsImplementers
+)
+
+// own reports whether the map of b flagged by bit is still the one of the
+// snapshot b was cloned from, and flags it as owned: the caller copies the
+// map before writing it when own returns true.
+func (b *bindings) own(bit uint8) bool {
+	shared := b.owned&bit == 0
+	b.owned |= bit
+	return shared
+}
+
+// cloneMap returns a copy of m with room for one more entry.
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	c := make(map[K]V, len(m)+1)
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// entry returns the binding of t in b.
+func (b *bindings) entry(t reflect.Type) (*binding, bool) {
+	return b.entries.get(t)
+}
+
+// set makes e the binding of t in b, which must not be published yet.
+func (b *bindings) set(t reflect.Type, e *binding) {
+	b.entries = b.entries.with(t, e)
+}
+
+// types returns the types mapped or provided in b, sorted by name.
+func (b *bindings) types() []reflect.Type {
+	types := make([]reflect.Type, 0, b.entries.size)
+	b.entries.each(func(t reflect.Type, _ *binding) {
+		types = append(types, t)
+	})
+	sortTypes(types)
+	return types
+}
+
+// providedTypes returns the types provided in b, sorted by name.
+func (b *bindings) providedTypes() []reflect.Type {
+	var types []reflect.Type
+	b.entries.each(func(t reflect.Type, e *binding) {
+		if e.provider != nil {
+			types = append(types, t)
+		}
+	})
+	sortTypes(types)
+	return types
+}
+
+// snapshot returns the bindings currently published by the injector.
+func (i *injector) snapshot() *bindings {
+	return i.current.Load()
+}
+
+// update publishes a copy of the current bindings modified by fn. Writers
+// are serialized, readers keep using the snapshot they loaded. While a
+// batch is being staged, see Activate, fn modifies the staged copy, which
+// is published as a whole.
+func (i *injector) update(fn func(*bindings)) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.staged != nil {
+		fn(i.staged)
+		return
+	}
+	b := i.current.Load().clone()
+	fn(b)
+	i.current.Store(b)
+}
+
+// stage runs fn with every binding it registers staged, then publishes
+// them as a single snapshot. Stages are serialized.
+func (i *injector) stage(fn func()) {
+	i.staging.Lock()
+	defer i.staging.Unlock()
+	i.mu.Lock()
+	i.staged = i.current.Load().clone()
+	i.mu.Unlock()
+	defer func() {
+		i.mu.Lock()
+		i.current.Store(i.staged)
+		i.staged = nil
+		i.mu.Unlock()
+	}()
+	fn()
+}
+
+// clone returns a copy of old that can be modified before it is published.
+// The copy shares the trie and the maps of old until they are modified.
+func (old *bindings) clone() *bindings {
+	b := *old
+	b.owned = 0
+	return &b
+}
+
+// call is the state of a single Invoke, Apply, Construct or Populate. Every
+// injector involved is read from the snapshot it published when the call
+// first reached it, so that a call completes against the epoch it started
+// with even if bindings are changed concurrently.
+type call struct {
+	origin *injector
+	// ctx is the context providers are built with, if any.
+	ctx context.Context
+	// values are the values carried by ctx, see WithValues, resolved for
+	// the injector the call started from before its bindings.
+	values map[reflect.Type]reflect.Value
+	// observe, if set, is called with the time every provider function of
+	// the call took to run.
+	observe func(reflect.Type, time.Duration)
+	// trace, if set, collects the steps of every lookup, see InvokeTraced.
+	trace *[]TraceStep
+	// progress, if set, records the steps of a call with a context, see
+	// AbortedError.
+	progress *[]string
+	// applied, if set, is called with every field Apply sets.
+	applied func(reflect.StructField)
+	// consumer is the argument or field being resolved, see Target.
+	consumer consumer
+	// extras are consulted by the injector the call started from after its
+	// parents, see InvokeUsing.
+	extras []Injector
+	// dry calls resolve the dependencies without calling any function or
+	// provider and without side effects, see ExplainInvoke; those of
+	// Validate, with marks set, record the bindings they reach as used.
+	dry, marks bool
+	// found, if set, records where the last dependency was found, hops
+	// being the number of parents the current lookup was delegated to.
+	found *found
+	hops  int
+	// held holds the Serialized bindings locked by the call and the calls
+	// it continues, locked those locked by the call itself while locking
+	// is positive, see Serialized.
+	held    map[*binding]bool
+	locked  []*binding
+	locking int
+	// first and epoch hold the snapshot of the first injector the call
+	// reached until epochs is allocated.
+	first     *injector
+	epoch     *bindings
+	epochs    map[*injector]*bindings
+	providing map[*provider]bool
+	// referring holds the MapRef bindings the call is resolving through,
+	// allocated by the first of them.
+	referring map[*binding]bool
+	guards    []guard
+}
+
+func newCall(origin *injector) *call {
+	// epochs and providing are allocated on first use: most calls resolve
+	// a single mapped value, for which the allocations dominate.
+	return &call{origin: origin}
+}
+
+// within makes c a call with the context ctx and the values it carries,
+// see InvokeWithContext, and returns c.
+func (c *call) within(ctx context.Context) *call {
+	c.ctx = ctx
+	c.values, _ = ctx.Value(valuesKey{}).(map[reflect.Type]reflect.Value)
+	c.progress = new([]string)
+	return c
+}
+
+// at returns a call continuing c from origin, which is used to invoke the
+// providers of origin.
+func (c *call) at(origin *injector) *call {
+	// both calls have to share the maps
+	c.share()
+	return &call{origin: origin, ctx: c.ctx, observe: c.observe, trace: c.trace, progress: c.progress, consumer: c.consumer, dry: c.dry, marks: c.marks, found: c.found, hops: c.hops, held: c.held, epochs: c.epochs, providing: c.providing, referring: c.referring}
+}
+
+// done returns the error reported instead of building t once the context
+// of the call is done.
+func (c *call) done(t reflect.Type) error {
+	if c.ctx == nil || c.ctx.Err() == nil {
+		return nil
+	}
+	var progress []string
+	if c.progress != nil {
+		progress = append(progress, *c.progress...)
+	}
+	return &AbortedError{t, c.ctx.Err(), progress}
+}
+
+// advance records a step completed by c, see AbortedError: the field of t
+// set or, if field is empty, the value of t built. The step is only
+// formatted for a call with a context.
+func (c *call) advance(t reflect.Type, field string) {
+	if c.progress == nil {
+		return
+	}
+	step := fmt.Sprintf("built %v", t)
+	if field != "" {
+		step = fmt.Sprintf("set %v.%s", t, field)
+	}
+	*c.progress = append(*c.progress, step)
+}
+
+// bindings returns the snapshot of i used by the call.
+func (c *call) bindings(i *injector) *bindings {
+	if c.epochs == nil {
+		if c.first == nil || c.first == i {
+			if c.first == nil {
+				c.first, c.epoch = i, i.snapshot()
+			}
+			return c.epoch
+		}
+		c.share()
+	}
+	b, ok := c.epochs[i]
+	if !ok {
+		b = i.snapshot()
+		c.epochs[i] = b
+	}
+	return b
+}
+
+// share allocates the maps of c that are not allocated yet, moving the
+// epoch of the first injector into epochs.
+func (c *call) share() {
+	if c.epochs == nil {
+		c.epochs = make(map[*injector]*bindings)
+		if c.first != nil {
+			c.epochs[c.first] = c.epoch
+		}
+	}
+	if c.providing == nil {
+		c.providing = make(map[*provider]bool)
+	}
+}
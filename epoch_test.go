@@ -0,0 +1,140 @@
+package inject_test
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorInvokeCompletesAgainstItsEpoch(t *testing.T) {
+	injector := inject.New()
+	injector.Map("before")
+	injector.Provide(func(inj inject.Injector) *Config {
+		inj.Map("after")
+		return &Config{}
+	})
+
+	_, err := injector.Invoke(func(c *Config, s string) {
+		expect(t, s, "before")
+	})
+	expect(t, err, nil)
+	expect(t, injector.Get(reflect.TypeOf("")).String(), "after")
+}
+
+func Test_InjectorConcurrentRebinding(t *testing.T) {
+	injector := inject.New()
+	injector.Map(0)
+	injector.Provide(func() *Config { return &Config{} })
+
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(2)
+		go func(n int) {
+			defer wg.Done()
+			for k := 0; k < 100; k++ {
+				injector.Map(n).Map(fmt.Sprint(k))
+			}
+		}(n)
+		go func() {
+			defer wg.Done()
+			for k := 0; k < 100; k++ {
+				if _, err := injector.Invoke(func(int, *Config) {}); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func Test_InjectorManyBindings(t *testing.T) {
+	injector := inject.New()
+	child := injector.Child()
+	types := make([]reflect.Type, 2000)
+	for n := range types {
+		types[n] = reflect.ArrayOf(n, reflect.TypeOf(0))
+		injector.Set(types[n], reflect.ValueOf(n))
+	}
+	// rebinding replaces the binding in the snapshots published since
+	for n := 0; n < len(types); n += 2 {
+		child.Set(types[n], reflect.ValueOf(-n))
+	}
+	for n, typ := range types {
+		expect(t, injector.Get(typ).Interface(), n)
+		if n%2 == 0 {
+			expect(t, child.Get(typ).Interface(), -n)
+		}
+	}
+	count := 0
+	injector.ForEach(func(inject.Binding) bool {
+		count++
+		return true
+	})
+	expect(t, count, len(types))
+	expect(t, injector.Get(reflect.ArrayOf(len(types), reflect.TypeOf(0))).IsValid(), false)
+}
+
+func BenchmarkInjectorGet(b *testing.B) {
+	injector := inject.New()
+	injector.Map(&Config{})
+	t := reflect.TypeOf(&Config{})
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		injector.Get(t)
+	}
+}
+
+func BenchmarkInjectorMap(b *testing.B) {
+	injector := inject.New()
+	cfg := &Config{}
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		injector.Map(cfg)
+	}
+}
+
+func BenchmarkInjectorRequest(b *testing.B) {
+	app := inject.New()
+	app.Map(&Config{})
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		req := app.Child()
+		req.Map("name").Map(1).Map(1.5)
+		req.Invoke(func(*Config, string, int, float64) {})
+	}
+}
+
+type handlerDeps struct {
+	Config *Config `inject`
+	Name   string  `inject`
+	Level  int     `inject`
+}
+
+func BenchmarkInjectorApply(b *testing.B) {
+	injector := inject.New()
+	injector.Map(&Config{}).Map("name").Map(1)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		var deps handlerDeps
+		injector.Apply(&deps)
+	}
+}
+
+type taggedDeps struct {
+	Config *Config `inject:"preset=app"`
+	Name   string  `inject`
+	Port   int     `inject:"" env:"INJECT_BENCH_PORT" default:"8080"`
+}
+
+func BenchmarkInjectorApplyTagged(b *testing.B) {
+	injector := inject.New(inject.TagPreset("app", "name=primary"))
+	injector.MapWith(&Config{}, inject.Named("primary")).Map("name")
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		var deps taggedDeps
+		injector.Apply(&deps)
+	}
+}
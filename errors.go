@@ -0,0 +1,226 @@
+package inject
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrNotFound is matched by errors.Is for every error reporting that a type
+// could not be resolved.
+var ErrNotFound = errors.New("inject: value not found")
+
+// NotFoundError is returned when no value can be resolved for Type. Hints
+// lists mapped types the requested type was likely confused with.
+type NotFoundError struct {
+	Type  reflect.Type
+	Hints []string
+	// Reason, if set, tells why the type could not be resolved anyway,
+	// e.g. why TestMode could not fake it.
+	Reason string
+}
+
+func (e *NotFoundError) Error() string {
+	msg := fmt.Sprintf("Value not found for type %v", e.Type)
+	if e.Reason != "" {
+		msg += ", " + e.Reason
+	}
+	if len(e.Hints) == 0 {
+		return msg
+	}
+	return fmt.Sprintf("%s (did you mean %s?)", msg, strings.Join(e.Hints, ", "))
+}
+
+// Is reports whether target is ErrNotFound.
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// candidate is a bound type along with its binding, which tells the
+// location it was registered at.
+type candidate struct {
+	typ    reflect.Type
+	e      *binding
+	strict bool
+}
+
+// missing reports whether err tells that the requested type itself is not
+// bound, as opposed to a failure while building it, which may wrap the
+// NotFoundError of one of its dependencies.
+func missing(err error) bool {
+	if err == ErrNotFound {
+		return true
+	}
+	_, ok := err.(*NotFoundError)
+	return ok
+}
+
+// ErrCycle is matched by errors.Is for every error reporting a dependency
+// cycle.
+var ErrCycle = errors.New("inject: dependency cycle")
+
+// CycleError is returned when building Type requires Type itself.
+type CycleError struct {
+	Type reflect.Type
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("Dependency cycle detected while building %v", e.Type)
+}
+
+// Is reports whether target is ErrCycle.
+func (e *CycleError) Is(target error) bool {
+	return target == ErrCycle
+}
+
+// ProviderError is returned when the provider of Type fails, either because
+// its own arguments cannot be injected or because it returned an error, and
+// when the PostConstruct hook of a struct wired by Construct fails.
+type ProviderError struct {
+	Type reflect.Type
+	Err  error
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("Provider for type %v failed: %v", e.Type, e.Err)
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// AbortedError is returned when the construction of Type is not attempted
+// because the context of the resolution is done. Err is the error of the
+// context, so that errors.Is matches context.Canceled or
+// context.DeadlineExceeded.
+type AbortedError struct {
+	Type reflect.Type
+	Err  error
+	// Progress lists the steps the resolution completed before it was
+	// aborted, in order: "built T" for every value built by a provider and
+	// "set S.F" for every field set by Apply or Construct. It tells which
+	// dependency a startup stuck on without a debugger.
+	Progress []string
+}
+
+func (e *AbortedError) Error() string {
+	msg := fmt.Sprintf("Construction of type %v aborted: %v", e.Type, e.Err)
+	if len(e.Progress) > 0 {
+		msg += fmt.Sprintf(" (after %s)", strings.Join(e.Progress, ", "))
+	}
+	return msg
+}
+
+func (e *AbortedError) Unwrap() error {
+	return e.Err
+}
+
+// InjectionError is returned by Invoke, Apply, Construct and Populate when
+// a dependency of Target cannot be injected.
+type InjectionError struct {
+	// Target is the function or struct type being injected.
+	Target string
+	// Name is "#n" for the n-th argument of a function, the field name for
+	// a struct field.
+	Name string
+	Type reflect.Type
+	Err  error
+}
+
+func (e *InjectionError) Error() string {
+	return fmt.Sprintf("Cannot inject %s %v of %s: %v", e.Name, e.Type, e.Target, e.Err)
+}
+
+func (e *InjectionError) Unwrap() error {
+	return e.Err
+}
+
+// typeLister is implemented by injectors that can enumerate the types
+// they are able to resolve, including those of their parents.
+type typeLister interface {
+	mappedTypes() []candidate
+}
+
+// mappedTypes returns every type mapped or provided in the injector and its
+// parent chain, leaving out the types the delegation policy or the parent
+// filters keep lookup from asking a parent for.
+func (i *injector) mappedTypes() []candidate {
+	b := i.snapshot()
+	var types []candidate
+	for _, t := range b.types() {
+		e, _ := b.entry(t)
+		types = append(types, candidate{t, e, e.strict})
+	}
+	for _, p := range b.parents {
+		if l, ok := p.inj.(typeLister); ok {
+			for _, c := range l.mappedTypes() {
+				if i.delegates(c.typ) && p.allows(c.typ) {
+					types = append(types, c)
+				}
+			}
+		}
+	}
+	return types
+}
+
+// notFound builds the error reported when t cannot be resolved. The error
+// lists mapped types that t was likely confused with, along with the
+// location they were registered at.
+func (i *injector) notFound(t reflect.Type) error {
+	return &NotFoundError{Type: t, Hints: suggest(t, i.mappedTypes()), Reason: i.unfaked(t)}
+}
+
+// underlying reports whether u, a predeclared or unnamed type, is the
+// underlying type of t, e.g. string for a type Named string. Pointers are
+// left out: two named types sharing an underlying struct, which their
+// pointers convert between, are unrelated more often than not.
+func underlying(t, u reflect.Type) bool {
+	if u.PkgPath() != "" || t.PkgPath() == "" || u.Kind() != t.Kind() {
+		return false
+	}
+	switch u.Kind() {
+	case reflect.Ptr, reflect.UnsafePointer, reflect.Interface:
+		return false
+	}
+	return t.ConvertibleTo(u)
+}
+
+// suggest returns a description of every candidate that is a near-miss for
+// the requested type: a type with the same name from another package, the
+// pointer or element type of t, a type whose underlying type is t or the
+// reverse, or a type implementing the requested interface.
+func suggest(t reflect.Type, candidates []candidate) []string {
+	seen := make(map[reflect.Type]bool)
+	var hints []string
+	for _, cand := range candidates {
+		c := cand.typ
+		if c == t || seen[c] {
+			continue
+		}
+		seen[c] = true
+
+		var reason string
+		switch {
+		case c.Name() != "" && c.Name() == t.Name() && c.PkgPath() != t.PkgPath():
+			reason = "same name in package " + c.PkgPath()
+		case c.Kind() == reflect.Ptr && c.Elem() == t:
+			reason = "pointer to the requested type"
+		case t.Kind() == reflect.Ptr && t.Elem() == c:
+			reason = "value of the requested pointer type"
+		case t.Kind() == reflect.Interface && implements(c, t):
+			reason = "implements the requested interface, map it with MapTo"
+		case underlying(c, t) || underlying(t, c):
+			reason = "same underlying type"
+		default:
+			continue
+		}
+		if source := cand.e.source(); source != "" {
+			reason += ", registered at " + source
+		}
+		hints = append(hints, fmt.Sprintf("%v: %s", c, reason))
+	}
+	sort.Strings(hints)
+	return hints
+}
@@ -0,0 +1,144 @@
+package inject_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type Named string
+
+func Test_InjectorNotFoundHints(t *testing.T) {
+	injector := inject.New()
+	injector.Map(&Config{}).Map(Named("named"))
+
+	_, err := injector.Invoke(func(Config) {})
+	refute(t, err, nil)
+	expect(t, strings.Contains(err.Error(), "did you mean *inject_test.Config"), true)
+
+	_, err = injector.Invoke(func(string) {})
+	refute(t, err, nil)
+	expect(t, strings.Contains(err.Error(), "inject_test.Named: same underlying type"), true)
+
+	_, err = injector.Invoke(func(SpecialString) {})
+	refute(t, err, nil)
+	expect(t, strings.Contains(err.Error(), "map it with MapTo"), true)
+
+	_, err = injector.Invoke(func(int) {})
+	refute(t, err, nil)
+	expect(t, strings.Contains(err.Error(), "did you mean"), false)
+}
+
+type hintLogger struct{}
+type hintStore struct{}
+
+func Test_InjectorNotFoundHintsUnrelatedTypes(t *testing.T) {
+	injector := inject.New()
+	injector.Map(&hintLogger{}).Map(hintLogger{})
+
+	_, err := injector.Invoke(func(*hintStore) {})
+	refute(t, err, nil)
+	expect(t, strings.Contains(err.Error(), "did you mean"), false)
+
+	_, err = injector.Invoke(func(hintStore) {})
+	refute(t, err, nil)
+	expect(t, strings.Contains(err.Error(), "did you mean"), false)
+}
+
+func Test_InjectorErrNotFound(t *testing.T) {
+	parent := inject.New()
+	parent.Map(&Config{})
+	injector := inject.New()
+	injector.SetParent(parent)
+
+	_, err := injector.Invoke(func(Config) {})
+	expect(t, errors.Is(err, inject.ErrNotFound), true)
+
+	var nf *inject.NotFoundError
+	expect(t, errors.As(err, &nf), true)
+	expect(t, nf.Type, reflect.TypeOf(Config{}))
+	expect(t, len(nf.Hints), 1)
+
+	injector.Provide(func() (int, error) { return 0, errors.New("boom") })
+	_, err = injector.Invoke(func(int) {})
+	refute(t, err, nil)
+	expect(t, errors.Is(err, inject.ErrNotFound), false)
+}
+
+func Test_InjectorNotFoundHintsLocation(t *testing.T) {
+	injector := inject.New()
+	injector.Map(&Config{})
+
+	_, err := injector.Invoke(func(Config) {})
+	expect(t, strings.Contains(err.Error(), "registered at errors_test.go:"), true)
+}
+
+type cyclic struct {
+	Next *cyclic `inject`
+}
+
+func Test_InjectorErrorChains(t *testing.T) {
+	boom := errors.New("boom")
+	injector := inject.New()
+	injector.Provide(func(s string) (int, error) { return len(s), nil })
+	injector.Provide(func() (float64, error) { return 0, boom })
+
+	_, err := injector.Invoke(func(int) {})
+	var ie *inject.InjectionError
+	expect(t, errors.As(err, &ie), true)
+	expect(t, ie.Name, "#0")
+	expect(t, ie.Type, reflect.TypeOf(0))
+	var pe *inject.ProviderError
+	expect(t, errors.As(err, &pe), true)
+	expect(t, pe.Type, reflect.TypeOf(0))
+	expect(t, errors.Is(err, inject.ErrNotFound), true)
+
+	_, err = injector.Invoke(func(float64) {})
+	expect(t, errors.Is(err, boom), true)
+
+	s := struct {
+		F float64 `inject`
+	}{}
+	err = injector.Apply(&s)
+	expect(t, errors.As(err, &ie), true)
+	expect(t, ie.Name, "F")
+	expect(t, errors.Is(err, boom), true)
+
+	var c *cyclic
+	err = injector.Construct(&c)
+	expect(t, errors.Is(err, inject.ErrCycle), true)
+	var ce *inject.CycleError
+	expect(t, errors.As(err, &ce), true)
+	expect(t, ce.Type, reflect.TypeOf(&cyclic{}))
+}
+
+func Test_InjectorPopulateConstructErrors(t *testing.T) {
+	injector := inject.New()
+	var ie *inject.InjectionError
+
+	var n int
+	err := injector.Populate(n)
+	expect(t, errors.As(err, &ie), true)
+	expect(t, ie.Target, "Populate")
+	expect(t, ie.Name, "#0")
+
+	var config *Config
+	err = injector.Populate(&config)
+	expect(t, errors.As(err, &ie), true)
+	expect(t, ie.Type, reflect.TypeOf(config))
+	expect(t, errors.Is(err, inject.ErrNotFound), true)
+
+	err = injector.Construct(&n)
+	expect(t, errors.As(err, &ie), true)
+	expect(t, ie.Target, "Construct")
+	expect(t, errors.Is(err, inject.ErrNotFound), false)
+
+	var f *Failing
+	err = injector.Construct(&f)
+	var pe *inject.ProviderError
+	expect(t, errors.As(err, &pe), true)
+	expect(t, pe.Type, reflect.TypeOf(f))
+}
@@ -0,0 +1,177 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Source tells where a dependency is resolved from.
+type Source string
+
+// Sources reported by ExplainInvoke.
+const (
+	// FromBinding is a value mapped with Map, MapTo or Set.
+	FromBinding Source = "binding"
+	// FromProvider is a value built by a provider.
+	FromProvider Source = "provider"
+	// FromInjector is the requesting injector itself.
+	FromInjector Source = "injector"
+	// FromFake is a fake generated in test mode.
+	FromFake Source = "fake"
+	// Unresolved means the dependency cannot be resolved.
+	Unresolved Source = "unresolved"
+	// Skipped is a tagged field that Apply ignores because it is
+	// unexported.
+	Skipped Source = "skipped"
+	// FromEnv is a field parsed from the environment variable named by
+	// its env tag.
+	FromEnv Source = "env"
+	// FromDefault is a field parsed from its default tag because its type
+	// cannot be resolved.
+	FromDefault Source = "default"
+	// FromGroup is a slice field gathering every bound value of its
+	// element type.
+	FromGroup Source = "group"
+	// FromTarget is the Target describing the consumer of a provider.
+	FromTarget Source = "target"
+	// FromCallContext is the context of the call, or context.Background.
+	FromCallContext Source = "context"
+	// FromNamed is a map field gathering every named binding of its
+	// element type.
+	FromNamed Source = "named"
+	// FromImplementation is an interface resolved from the bound type
+	// implementing it, see Implementations.
+	FromImplementation Source = "implementation"
+)
+
+// found is where a dependency was found, see call.found.
+type found struct {
+	source Source
+	depth  int
+	scope  Scope
+}
+
+// find records that inj found the dependency c is resolving from src.
+func (c *call) find(src Source, inj Injector) {
+	if c.found != nil {
+		*c.found = found{src, c.hops, inj.Scope()}
+	}
+}
+
+// Explanation describes how a dependency would be resolved.
+type Explanation struct {
+	// Name identifies the dependency: "#n" for the n-th argument of a
+	// function, the field name for a struct field.
+	Name string
+	Type reflect.Type
+	// Tag is the struct tag of a field.
+	Tag reflect.StructTag
+	// Source tells where the dependency is resolved from.
+	Source Source
+	// Depth is the number of parent hops to the injector holding the
+	// binding, 0 for the requesting injector itself.
+	Depth int
+	// Scope is the scope of the injector holding the binding.
+	Scope Scope
+	// Err is the error reported for an unresolved dependency.
+	Err error
+}
+
+func (e Explanation) String() string {
+	s := fmt.Sprintf("%s %v: %s", e.Name, e.Type, e.Source)
+	if e.Tag != "" {
+		s = fmt.Sprintf("%s %v `%s`: %s", e.Name, e.Type, e.Tag, e.Source)
+	}
+	if e.Source == Unresolved {
+		return s + ": " + e.Err.Error()
+	}
+	if e.Depth > 0 {
+		s += fmt.Sprintf(" from parent at depth %d", e.Depth)
+	}
+	if e.Scope != "" {
+		s += fmt.Sprintf(" in scope %s", e.Scope)
+	}
+	return s
+}
+
+// ExplainInvoke reports, for each argument of f, where Invoke would resolve
+// it from, without calling f or any provider.
+// It panics if f is not a function.
+func (i *injector) ExplainInvoke(f interface{}) []Explanation {
+	t := reflect.TypeOf(f)
+	if t == nil || t.Kind() != reflect.Func {
+		panic("Called inject.ExplainInvoke with a value that is not a function")
+	}
+
+	c := i.dryCall()
+	explanations := make([]Explanation, t.NumIn())
+	for n := range explanations {
+		_, err := i.argument(f, n, c)
+		explanations[n] = explanation(fmt.Sprintf("#%d", n), t.In(n), c, err)
+	}
+	return explanations
+}
+
+// ExplainApply reports, for each tagged field of the struct val is or
+// points to, how Apply would inject it, without modifying val or calling
+// any provider. Returns an error if val is not a struct or a pointer to a
+// struct.
+func (i *injector) ExplainApply(val interface{}) ([]Explanation, error) {
+	t := reflect.TypeOf(val)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Cannot explain %v, expected a struct", reflect.TypeOf(val))
+	}
+
+	c := i.dryCall()
+	var explanations []Explanation
+	for _, f := range i.injectable(t) {
+		e := Explanation{Name: f.Name, Type: f.Type, Source: Skipped}
+		if f.PkgPath == "" {
+			_, err := i.fieldValue(t, &f, c)
+			e = explanation(f.Name, f.Type, c, err)
+		}
+		e.Tag = f.Tag
+		explanations = append(explanations, e)
+	}
+	return explanations, nil
+}
+
+// ApplyReport injects the tagged fields of val like Apply and reports, in
+// the order of the fields, how each field it set was injected, so that
+// callers can log their wiring at startup and tests can assert it. When
+// the injection fails, the report holds the fields set before the failure.
+func (i *injector) ApplyReport(val interface{}) ([]Explanation, error) {
+	var report []Explanation
+	c := newCall(i)
+	c.found = new(found)
+	c.applied = func(f reflect.StructField) {
+		e := explanation(f.Name, f.Type, c, nil)
+		e.Tag = f.Tag
+		report = append(report, e)
+	}
+	err := i.apply(val, c)
+	return report, err
+}
+
+// dryCall returns a dry call from the injector recording where the
+// dependencies it resolves are found, see ExplainInvoke.
+func (i *injector) dryCall() *call {
+	c := newCall(i)
+	c.dry, c.found = true, new(found)
+	return c
+}
+
+// explanation explains the dependency name of type t that c has just
+// resolved, failing with err, and clears the record of c for the next
+// one.
+func explanation(name string, t reflect.Type, c *call, err error) Explanation {
+	e := Explanation{Name: name, Type: t, Source: c.found.source, Depth: c.found.depth, Scope: c.found.scope}
+	if err != nil {
+		e = Explanation{Name: name, Type: t, Source: Unresolved, Err: err}
+	}
+	*c.found = found{}
+	return e
+}
@@ -0,0 +1,144 @@
+package inject_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorExplainInvoke(t *testing.T) {
+	app := inject.New()
+	app.SetScope(inject.Singleton)
+	app.Map("app dep")
+	app.Provide(func() *Config { return &Config{} })
+
+	request := inject.New()
+	request.SetParent(app)
+	request.SetScope(inject.Request)
+	request.Map(42)
+
+	called := false
+	explanations := request.ExplainInvoke(func(int, string, *Config, inject.Injector, float64) {
+		called = true
+	})
+	expect(t, called, false)
+	expect(t, len(explanations), 5)
+
+	expect(t, explanations[0].Source, inject.FromBinding)
+	expect(t, explanations[0].Depth, 0)
+	expect(t, explanations[0].Scope, inject.Request)
+	expect(t, explanations[1].Source, inject.FromBinding)
+	expect(t, explanations[1].Depth, 1)
+	expect(t, explanations[1].Scope, inject.Singleton)
+	expect(t, explanations[1].String(), "#1 string: binding from parent at depth 1 in scope singleton")
+	expect(t, explanations[2].Source, inject.FromProvider)
+	expect(t, explanations[3].Source, inject.FromInjector)
+	expect(t, explanations[4].Source, inject.Unresolved)
+	expect(t, errors.Is(explanations[4].Err, inject.ErrNotFound), true)
+}
+
+func Test_InjectorExplainApply(t *testing.T) {
+	injector := inject.New()
+	injector.Map("a dep")
+
+	explanations, err := injector.ExplainApply(&struct {
+		Dep     string  `inject`
+		Missing *Config `inject`
+		hidden  string  `inject`
+		Plain   string
+	}{})
+	expect(t, err, nil)
+	expect(t, len(explanations), 3)
+	expect(t, explanations[0].Name, "Dep")
+	expect(t, explanations[0].Source, inject.FromBinding)
+	expect(t, explanations[0].String(), "Dep string `inject`: binding")
+	expect(t, explanations[1].Source, inject.Unresolved)
+	expect(t, explanations[2].Source, inject.Skipped)
+
+	_, err = injector.ExplainApply(42)
+	refute(t, err, nil)
+}
+
+func Test_InjectorApplyReport(t *testing.T) {
+	parent := inject.New()
+	parent.Map("parent")
+	injector := parent.Child()
+	injector.Provide(func() int { return 1 })
+
+	s := struct {
+		S       string `inject`
+		N       int    `inject`
+		F       float64
+		private string `inject`
+		Missing bool   `inject`
+	}{}
+	report, err := injector.ApplyReport(&s)
+	refute(t, err, nil)
+	expect(t, len(report), 2)
+	expect(t, report[0].Name, "S")
+	expect(t, report[0].Source, inject.FromBinding)
+	expect(t, report[0].Depth, 1)
+	expect(t, report[1].Source, inject.FromProvider)
+	expect(t, s.private, "")
+
+	injector.Map(true)
+	report, err = injector.ApplyReport(&s)
+	expect(t, err, nil)
+	expect(t, len(report), 3)
+}
+
+type explainInt int
+
+type explainOption func(*Config)
+
+func Test_InjectorExplainFollowsLookup(t *testing.T) {
+	platform := inject.New()
+	platform.SetScope(inject.Singleton)
+	platform.Provide(func() *Config { return &Config{} })
+
+	injector := inject.New(inject.Conversions(), inject.Statistics())
+	injector.Map(explainInt(3))
+	injector.MapRef(platform, reflect.TypeOf(&Config{}))
+	injector.MapWith(explainOption(func(*Config) {}), inject.Named("timeout"))
+
+	f := func(context.Context, int, *Config, []explainOption) {}
+	_, err := injector.Invoke(f)
+	expect(t, err, nil)
+	resolved := injector.(inject.Diagnostics).Stats()[reflect.TypeOf(explainInt(0))].Count
+
+	explanations := injector.ExplainInvoke(f)
+	expect(t, explanations[0].Source, inject.FromCallContext)
+	expect(t, explanations[1].Source, inject.FromBinding)
+	expect(t, explanations[2].Source, inject.FromProvider)
+	expect(t, explanations[2].Scope, inject.Singleton)
+	expect(t, explanations[3].Source, inject.FromGroup)
+	expect(t, injector.(inject.Diagnostics).Stats()[reflect.TypeOf(explainInt(0))].Count, resolved)
+
+	fakes := inject.New(inject.TestMode())
+	expect(t, fakes.ExplainInvoke(func(func() int) {})[0].Source, inject.FromFake)
+}
+
+type explainNamer interface {
+	Name() string
+}
+
+type explainService struct{}
+
+func (explainService) Name() string { return "service" }
+
+func Test_InjectorExplainImplementation(t *testing.T) {
+	app := inject.New(inject.Implementations())
+	app.SetScope(inject.Singleton)
+	app.Map(explainService{})
+	request := app.Child()
+
+	explanations := request.ExplainInvoke(func(explainNamer, interface{ Name() string }, explainService) {})
+	expect(t, explanations[0].Source, inject.FromImplementation)
+	expect(t, explanations[0].Depth, 1)
+	expect(t, explanations[0].Scope, inject.Singleton)
+	expect(t, explanations[1].Source, inject.FromImplementation)
+	expect(t, explanations[2].Source, inject.FromBinding)
+}
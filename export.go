@@ -0,0 +1,29 @@
+package inject
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ExportTo maps into dst the values the injector resolves for types, so
+// that a bootstrap injector can hand a curated subset of its bindings to a
+// sandboxed plugin injector without making itself the parent of dst.
+// Values built by providers are resolved, and thus built, by ExportTo, so
+// that dst shares them with the injector rather than building its own;
+// their dependencies are not exported. The types of Select can be passed
+// to export the bindings matching a predicate.
+// Returns an error joining the errors of the types that cannot be
+// resolved, which are not mapped into dst.
+func (i *injector) ExportTo(dst Injector, types ...reflect.Type) error {
+	var errs []error
+	c := newCall(i)
+	for _, t := range types {
+		val, err := i.lookup(t, c)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		dst.Set(t, val)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,33 @@
+package inject_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorExportTo(t *testing.T) {
+	built := 0
+	boot := inject.New()
+	boot.Map("secret").Map(alpha{}).Map(gamma{})
+	boot.Provide(func() *beta { built++; return &beta{} })
+
+	plugin := inject.New()
+	p := inject.InterfaceOf((*Plugin)(nil))
+	var types []reflect.Type
+	for _, b := range boot.Select(func(b inject.Binding) bool { return b.Type.Implements(p) }) {
+		types = append(types, b.Type)
+	}
+	expect(t, boot.ExportTo(plugin, types...), nil)
+	expect(t, built, 1)
+
+	expect(t, plugin.Get(reflect.TypeOf(alpha{})).IsValid(), true)
+	expect(t, plugin.Get(reflect.TypeOf(&beta{})).Interface() == boot.Get(reflect.TypeOf(&beta{})).Interface(), true)
+	expect(t, plugin.Get(reflect.TypeOf("")).IsValid(), false)
+	expect(t, built, 1)
+
+	err := boot.ExportTo(plugin, reflect.TypeOf(0))
+	expect(t, errors.Is(err, inject.ErrNotFound), true)
+}
@@ -0,0 +1,74 @@
+package inject
+
+import "reflect"
+
+// TestMode returns an Option that satisfies dependencies no binding can
+// resolve with generated fakes, so that unit tests only have to map the
+// dependencies they care about.
+// Missing function types are resolved to functions returning zero values
+// and missing empty interfaces to a non-nil value. Go cannot define methods
+// at run time, so interfaces with methods are not faked: resolving one
+// fails with a NotFoundError telling to map a stub.
+// A fake is generated once per type and reused afterwards.
+func TestMode() Option {
+	return func(i *injector) {
+		i.fakes = make(map[reflect.Type]reflect.Value)
+	}
+}
+
+// fake returns the fake for t, generating it if needed, or the zero value
+// of t for a dry call c, which generates nothing. It returns false if the
+// injector is not in test mode or if t cannot be faked.
+func (i *injector) fake(t reflect.Type, c *call) (reflect.Value, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.fakes == nil {
+		return reflect.Value{}, false
+	}
+	if v, ok := i.fakes[t]; ok {
+		return v, true
+	}
+	if c.dry {
+		return reflect.Zero(t), fakeable(t)
+	}
+
+	var v reflect.Value
+	switch t.Kind() {
+	case reflect.Func:
+		v = reflect.MakeFunc(t, func([]reflect.Value) []reflect.Value {
+			out := make([]reflect.Value, t.NumOut())
+			for n := range out {
+				out[n] = reflect.Zero(t.Out(n))
+			}
+			return out
+		})
+	case reflect.Interface:
+		if !fakeable(t) {
+			return reflect.Value{}, false
+		}
+		v = reflect.New(t).Elem()
+		v.Set(reflect.ValueOf(struct{}{}))
+	default:
+		return reflect.Value{}, false
+	}
+
+	i.fakes[t] = v
+	return v, true
+}
+
+// fakeable reports whether TestMode fakes t.
+func fakeable(t reflect.Type) bool {
+	return t.Kind() == reflect.Func || t.Kind() == reflect.Interface && t.NumMethod() == 0
+}
+
+// unfaked returns the reason TestMode of the injector did not fake t, if t
+// is an interface it cannot fake.
+func (i *injector) unfaked(t reflect.Type) string {
+	i.mu.Lock()
+	testing := i.fakes != nil
+	i.mu.Unlock()
+	if !testing || t.Kind() != reflect.Interface || fakeable(t) {
+		return ""
+	}
+	return "test mode cannot fake an interface with methods, map a stub of it"
+}
@@ -0,0 +1,40 @@
+package inject_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type Mailer interface {
+	Send(to string) error
+}
+
+func Test_InjectorTestMode(t *testing.T) {
+	injector := inject.New(inject.TestMode())
+	injector.Map("a dep")
+
+	_, err := injector.Invoke(func(s string, v interface{}, f func(int) (string, error)) {
+		expect(t, s, "a dep")
+		refute(t, v, nil)
+		out, err := f(42)
+		expect(t, out, "")
+		expect(t, err, nil)
+	})
+	expect(t, err, nil)
+	expect(t, injector.Validate(func(interface{}) {}), nil)
+
+	// interfaces with methods cannot be implemented at run time
+	_, err = injector.Invoke(func(Mailer) {})
+	expect(t, errors.Is(err, inject.ErrNotFound), true)
+	expect(t, strings.Contains(err.Error(), "test mode cannot fake an interface with methods"), true)
+	refute(t, injector.Validate(func(Mailer) {}), nil)
+
+	_, err = injector.Invoke(func(int) {})
+	refute(t, err, nil)
+
+	_, err = inject.New().Invoke(func(Mailer) {})
+	refute(t, err, nil)
+}
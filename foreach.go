@@ -0,0 +1,87 @@
+package inject
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Binding describes a binding of an injector to tooling walking the
+// bindings with ForEach, without exposing the Type map itself.
+type Binding struct {
+	Type reflect.Type
+	// Name is the name of a binding registered with the Named option.
+	Name string
+	// Scope is the scope of the injector holding the binding.
+	Scope Scope
+	// Depth is the number of parent hops to the injector holding the
+	// binding, 0 for the injector ForEach was called on.
+	Depth int
+	// Source is the file:line the binding was registered at.
+	Source string
+	// Provided is true for a binding built by a provider rather than a
+	// mapped value.
+	Provided bool
+}
+
+// ForEach calls fn with every binding of the injector and then with those
+// of its parents, depth first, until fn returns false. The bindings of an
+// injector are walked by type name, named bindings after the unnamed ones.
+// Parents that are not created by New are skipped.
+func (i *injector) ForEach(fn func(Binding) bool) {
+	i.forEach(0, func(b Binding, _ *binding) bool {
+		return fn(b)
+	})
+}
+
+// Select returns the bindings of the injector and its parents accepted by
+// predicate, in the order ForEach walks them, e.g. every binding whose
+// type implements an interface.
+func (i *injector) Select(predicate func(Binding) bool) []Binding {
+	var selected []Binding
+	i.ForEach(func(b Binding) bool {
+		if predicate(b) {
+			selected = append(selected, b)
+		}
+		return true
+	})
+	return selected
+}
+
+// forEach walks the bindings of the injector, depth hops away from the
+// injector ForEach was called on, and of its parents, calling fn with the
+// description of every binding and the binding itself. Returns false once
+// fn did.
+func (i *injector) forEach(depth int, fn func(Binding, *binding) bool) bool {
+	b := i.snapshot()
+	visit := func(t reflect.Type, name string, e *binding) bool {
+		return fn(Binding{t, name, b.scope, depth, e.source(), e.provider != nil}, e)
+	}
+	for _, t := range b.types() {
+		if e, _ := b.entry(t); !visit(t, "", e) {
+			return false
+		}
+	}
+
+	keys := make([]namedKey, 0, len(b.named))
+	for k := range b.named {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(a, c int) bool {
+		if keys[a].typ != keys[c].typ {
+			return keys[a].typ.String() < keys[c].typ.String()
+		}
+		return keys[a].name < keys[c].name
+	})
+	for _, k := range keys {
+		if !visit(k.typ, k.name, b.named[k]) {
+			return false
+		}
+	}
+
+	for _, p := range b.parents {
+		if pi, ok := p.inj.(*injector); ok && !pi.forEach(depth+1, fn) {
+			return false
+		}
+	}
+	return true
+}
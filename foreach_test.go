@@ -0,0 +1,53 @@
+package inject_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorForEach(t *testing.T) {
+	parent := inject.New()
+	parent.SetScope(inject.Singleton)
+	parent.Map(3.14)
+	injector := inject.New()
+	injector.SetParent(parent)
+	injector.Map("a").MapWith("b", inject.Named("second"))
+	injector.Provide(func() int { return 1 })
+
+	var all []inject.Binding
+	injector.ForEach(func(b inject.Binding) bool {
+		all = append(all, b)
+		return true
+	})
+	expect(t, len(all), 4)
+	expect(t, all[0].Type, reflect.TypeOf(0))
+	expect(t, all[0].Provided, true)
+	expect(t, all[1].Type, reflect.TypeOf(""))
+	expect(t, all[2].Name, "second")
+	expect(t, all[3].Depth, 1)
+	expect(t, all[3].Scope, inject.Singleton)
+	expect(t, strings.HasPrefix(all[3].Source, "foreach_test.go:"), true)
+
+	n := 0
+	injector.ForEach(func(inject.Binding) bool {
+		n++
+		return n < 2
+	})
+	expect(t, n, 2)
+}
+
+func Test_InjectorSelect(t *testing.T) {
+	injector := inject.New()
+	injector.Map(alpha{}).Map(gamma{}).Map(42)
+
+	plugin := inject.InterfaceOf((*Plugin)(nil))
+	selected := injector.Select(func(b inject.Binding) bool {
+		return b.Type.Implements(plugin)
+	})
+	expect(t, len(selected), 2)
+	expect(t, selected[0].Type, reflect.TypeOf(alpha{}))
+	expect(t, len(injector.Select(func(b inject.Binding) bool { return b.Provided })), 0)
+}
@@ -0,0 +1,64 @@
+package inject
+
+import (
+	"context"
+	"reflect"
+)
+
+// Future is the result of a function invoked by InvokeAsync.
+type Future struct {
+	done chan struct{}
+	out  []reflect.Value
+	err  error
+}
+
+// InvokeAsync resolves the arguments of f like Invoke and then calls f on
+// a new goroutine, returning at once. The values f returns, or the error
+// that prevented f from being called, are reported by the Wait method of
+// the returned Future. It lets startup tasks wired by the injector run in
+// the background while the program carries on. The Serialized bindings f
+// depends on are held until f returns, as they are by Invoke.
+// It panics if f is not a function.
+func (inj *injector) InvokeAsync(f interface{}) *Future {
+	fut := &Future{done: make(chan struct{})}
+	c := newCall(inj)
+	release := c.serialize()
+	guards := len(c.guards)
+	in, err := inj.arguments(f, c, nil)
+	if err != nil {
+		release()
+		fut.err = err
+		close(fut.done)
+		return fut
+	}
+
+	c.handOff()
+	go func() {
+		defer close(fut.done)
+		c.adopt()
+		defer release()
+		fn := reflect.ValueOf(f)
+		fut.out = inj.run(fn, in, c)
+		c.verify(guards, fn)
+	}()
+	return fut
+}
+
+// Done returns a channel closed once the function has returned or could
+// not be called.
+func (fut *Future) Done() <-chan struct{} {
+	return fut.done
+}
+
+// Wait waits for the function to return and returns the values it
+// returned, or the error that prevented it from being called. Returns the
+// error of ctx if ctx is done first; the function keeps running and Wait
+// can be called again.
+func (fut *Future) Wait(ctx context.Context) ([]reflect.Value, error) {
+	select {
+	case <-fut.done:
+		return fut.out, fut.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,37 @@
+package inject_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorInvokeAsync(t *testing.T) {
+	injector := inject.New()
+	injector.Map("dep")
+	release := make(chan struct{})
+	fut := injector.InvokeAsync(func(s string) string {
+		<-release
+		return s + "!"
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := fut.Wait(ctx)
+	expect(t, errors.Is(err, context.DeadlineExceeded), true)
+
+	close(release)
+	<-fut.Done()
+	out, err := fut.Wait(context.Background())
+	expect(t, err, nil)
+	expect(t, out[0].String(), "dep!")
+
+	called := false
+	fut = injector.InvokeAsync(func(int) { called = true })
+	_, err = fut.Wait(context.Background())
+	expect(t, errors.Is(err, inject.ErrNotFound), true)
+	expect(t, called, false)
+}
@@ -0,0 +1,118 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Node is a binding of a Graph along with the types its provider takes.
+type Node struct {
+	Binding
+	// Dependencies are the argument types of the provider of the binding,
+	// in order, and nil for a mapped value.
+	Dependencies []reflect.Type
+}
+
+// Graph is the dependency graph of an injector and its parents.
+type Graph struct {
+	// Nodes are the bindings of the injector and its parents, in the order
+	// ForEach walks them.
+	Nodes []Node
+}
+
+// Graph returns the dependency graph of the injector and its parents: every
+// binding along with the types its provider depends on. Dependencies are
+// read from the provider signatures, nothing is resolved or built.
+func (i *injector) Graph() Graph {
+	nodes, _ := i.nodes()
+	return Graph{nodes}
+}
+
+// nodes returns the nodes of the Graph of the injector along with their
+// bindings.
+func (i *injector) nodes() ([]Node, []*binding) {
+	var nodes []Node
+	var entries []*binding
+	i.forEach(0, func(b Binding, e *binding) bool {
+		n := Node{Binding: b}
+		if e.provider != nil {
+			ft := e.provider.fn.Type()
+			for k := 0; k < ft.NumIn(); k++ {
+				// the call BindIf and BindSplit continue is no dependency
+				if ft.In(k) != callType {
+					n.Dependencies = append(n.Dependencies, ft.In(k))
+				}
+			}
+		}
+		nodes = append(nodes, n)
+		entries = append(entries, e)
+		return true
+	})
+	return nodes, entries
+}
+
+// label returns the name of the node of a binding in a diagram.
+func (n Node) label() string {
+	if n.Name != "" {
+		return fmt.Sprintf("%v (%s)", n.Type, n.Name)
+	}
+	return n.Type.String()
+}
+
+// walk calls node with the label of every node of the graph and of every
+// type a node depends on that is not bound, in order, and then edge with the
+// labels of both ends of every dependency.
+func (g Graph) walk(node func(string), edge func(from, to string)) {
+	seen := make(map[string]bool)
+	visit := func(label string) {
+		if !seen[label] {
+			seen[label] = true
+			node(label)
+		}
+	}
+	for _, n := range g.Nodes {
+		visit(n.label())
+	}
+	for _, n := range g.Nodes {
+		for _, t := range n.Dependencies {
+			visit(t.String())
+		}
+	}
+	for _, n := range g.Nodes {
+		for _, t := range n.Dependencies {
+			edge(n.label(), t.String())
+		}
+	}
+}
+
+// DOT returns the graph in the DOT language of Graphviz, with an edge from
+// every provided type to each of its dependencies, e.g. to be rendered
+// with dot -Tsvg.
+func (g Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph inject {\n")
+	g.walk(func(label string) {
+		fmt.Fprintf(&b, "\t%q;\n", label)
+	}, func(from, to string) {
+		fmt.Fprintf(&b, "\t%q -> %q;\n", from, to)
+	})
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid returns the graph as a Mermaid flowchart, with an edge from every
+// provided type to each of its dependencies, which renders when pasted in a
+// mermaid code block of a Markdown document.
+func (g Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	ids := make(map[string]string)
+	g.walk(func(label string) {
+		ids[label] = fmt.Sprintf("n%d", len(ids))
+		fmt.Fprintf(&b, "\t%s[\"%s\"]\n", ids[label], strings.ReplaceAll(label, `"`, "#quot;"))
+	}, func(from, to string) {
+		fmt.Fprintf(&b, "\t%s --> %s\n", ids[from], ids[to])
+	})
+	return b.String()
+}
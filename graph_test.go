@@ -0,0 +1,51 @@
+package inject_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorGraph(t *testing.T) {
+	parent := inject.New()
+	parent.Map("dsn")
+	injector := parent.Child()
+	injector.Provide(func(dsn string, n int) *Config { return &Config{} })
+	injector.Map(3)
+
+	g := injector.(inject.Diagnostics).Graph()
+	expect(t, len(g.Nodes), 3)
+	expect(t, g.Nodes[0].Type, reflect.TypeOf(&Config{}))
+	expect(t, len(g.Nodes[0].Dependencies), 2)
+	expect(t, g.Nodes[0].Dependencies[0], reflect.TypeOf(""))
+	expect(t, g.Nodes[0].Dependencies[1], reflect.TypeOf(0))
+	expect(t, g.Nodes[1].Type, reflect.TypeOf(0))
+	expect(t, len(g.Nodes[1].Dependencies), 0)
+	expect(t, g.Nodes[2].Depth, 1)
+}
+
+func Test_GraphDiagrams(t *testing.T) {
+	injector := inject.New()
+	injector.Map("dsn").MapWith("replica", inject.Named("replica"))
+	injector.Provide(func(dsn string, inj inject.Injector) *Config { return &Config{} })
+	g := injector.(inject.Diagnostics).Graph()
+
+	expect(t, g.DOT(), `digraph inject {
+	"*inject_test.Config";
+	"string";
+	"string (replica)";
+	"inject.Injector";
+	"*inject_test.Config" -> "string";
+	"*inject_test.Config" -> "inject.Injector";
+}
+`)
+	expect(t, g.Mermaid(), `flowchart LR
+	n0["*inject_test.Config"]
+	n1["string"]
+	n2["string (replica)"]
+	n3["inject.Injector"]
+	n0 --> n1
+	n0 --> n3
+`)
+}
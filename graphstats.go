@@ -0,0 +1,143 @@
+package inject
+
+import (
+	"reflect"
+	"sort"
+)
+
+// GraphStats holds metrics of a dependency graph that point at the hot
+// spots of the wiring, see Graph.Stats.
+type GraphStats struct {
+	// MaxDepth is the number of edges of the longest chain of dependencies,
+	// the types of a cycle counting as one.
+	MaxDepth int
+	// FanIn is the number of providers depending on each type.
+	FanIn map[reflect.Type]int
+	// FanOut is the number of dependencies of each provided type.
+	FanOut map[reflect.Type]int
+	// Orphans are the bound types no provider depends on, sorted by name.
+	// They are either resolved by the invoked functions only or not used
+	// at all, see Unused.
+	Orphans []reflect.Type
+	// Cycles are the strongly connected components of the graph: the sets
+	// of types depending on each other, largest first, each sorted by name.
+	Cycles [][]reflect.Type
+}
+
+// Stats computes the metrics of the graph. Types bound several times, by
+// several injectors or under several names, are counted as one.
+func (g Graph) Stats() GraphStats {
+	s := GraphStats{FanIn: make(map[reflect.Type]int), FanOut: make(map[reflect.Type]int)}
+	deps := make(map[reflect.Type][]reflect.Type)
+	bound := make(map[reflect.Type]bool)
+	for _, n := range g.Nodes {
+		bound[n.Type] = true
+		if n.Provided {
+			s.FanOut[n.Type] = len(n.Dependencies)
+		}
+		for _, t := range n.Dependencies {
+			s.FanIn[t]++
+			deps[n.Type] = append(deps[n.Type], t)
+		}
+	}
+
+	types := make([]reflect.Type, 0, len(bound))
+	for t := range bound {
+		types = append(types, t)
+		if s.FanIn[t] == 0 {
+			s.Orphans = append(s.Orphans, t)
+		}
+	}
+	sortTypes(types)
+	sortTypes(s.Orphans)
+
+	components := components(types, deps)
+	component := make(map[reflect.Type]int)
+	for n, c := range components {
+		for _, t := range c {
+			component[t] = n
+		}
+		if len(c) > 1 || selfLoop(c[0], deps) {
+			sortTypes(c)
+			s.Cycles = append(s.Cycles, c)
+		}
+	}
+	sort.SliceStable(s.Cycles, func(a, b int) bool {
+		return len(s.Cycles[a]) > len(s.Cycles[b])
+	})
+
+	// Components are found dependencies first, so the depth of a component
+	// is known once the components it depends on have been visited.
+	depth := make([]int, len(components))
+	for n, c := range components {
+		for _, t := range c {
+			for _, dep := range deps[t] {
+				if d := component[dep]; d != n && depth[d]+1 > depth[n] {
+					depth[n] = depth[d] + 1
+				}
+			}
+		}
+		if depth[n] > s.MaxDepth {
+			s.MaxDepth = depth[n]
+		}
+	}
+	return s
+}
+
+// selfLoop reports whether t depends on itself.
+func selfLoop(t reflect.Type, deps map[reflect.Type][]reflect.Type) bool {
+	for _, dep := range deps[t] {
+		if dep == t {
+			return true
+		}
+	}
+	return false
+}
+
+// components returns the strongly connected components of the graph of
+// types and their dependencies, in reverse topological order, with
+// Tarjan's algorithm.
+func components(types []reflect.Type, deps map[reflect.Type][]reflect.Type) [][]reflect.Type {
+	var (
+		result  [][]reflect.Type
+		stack   []reflect.Type
+		onStack = make(map[reflect.Type]bool)
+		index   = make(map[reflect.Type]int)
+		low     = make(map[reflect.Type]int)
+	)
+	var visit func(reflect.Type)
+	visit = func(t reflect.Type) {
+		index[t] = len(index)
+		low[t] = index[t]
+		stack = append(stack, t)
+		onStack[t] = true
+		for _, dep := range deps[t] {
+			if _, ok := index[dep]; !ok {
+				visit(dep)
+				low[t] = min(low[t], low[dep])
+			} else if onStack[dep] {
+				low[t] = min(low[t], index[dep])
+			}
+		}
+		if low[t] != index[t] {
+			return
+		}
+		var c []reflect.Type
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			c = append(c, top)
+			if top == t {
+				break
+			}
+		}
+		result = append(result, c)
+	}
+	for _, t := range types {
+		if _, ok := index[t]; !ok {
+			visit(t)
+		}
+	}
+	return result
+}
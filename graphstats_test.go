@@ -0,0 +1,34 @@
+package inject_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_GraphStats(t *testing.T) {
+	type a struct{}
+	type b struct{}
+	injector := inject.New()
+	injector.Map("dsn")
+	injector.Map(1.5)
+	injector.Provide(func(dsn string) int { return 0 })
+	injector.Provide(func(n int, dsn string) *Config { return &Config{} })
+	injector.Provide(func(b) a { return a{} })
+	injector.Provide(func(a, *Config) b { return b{} })
+
+	s := injector.(inject.Diagnostics).Graph().Stats()
+	stringType, intType := reflect.TypeOf(""), reflect.TypeOf(0)
+	expect(t, s.FanIn[stringType], 2)
+	expect(t, s.FanIn[intType], 1)
+	expect(t, s.FanOut[reflect.TypeOf(&Config{})], 2)
+	expect(t, s.FanOut[stringType], 0)
+	expect(t, len(s.Orphans), 1)
+	expect(t, s.Orphans[0], reflect.TypeOf(1.5))
+	expect(t, len(s.Cycles), 1)
+	expect(t, len(s.Cycles[0]), 2)
+	expect(t, s.Cycles[0][0], reflect.TypeOf(a{}))
+	// b and a, then *Config, int and string.
+	expect(t, s.MaxDepth, 3)
+}
@@ -0,0 +1,182 @@
+package inject
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// assignable returns the types bound in the injector and its parents that
+// are assignable to t. Types are ordered by the injector they are bound
+// in, the injector itself first, then by name.
+func (i *injector) assignable(t reflect.Type) []reflect.Type {
+	var types []reflect.Type
+	seen := make(map[reflect.Type]bool)
+	for _, cand := range i.mappedTypes() {
+		if !seen[cand.typ] && cand.typ.AssignableTo(t) {
+			seen[cand.typ] = true
+			types = append(types, cand.typ)
+		}
+	}
+	return types
+}
+
+// group resolves a slice of type t holding every value bound in the
+// injector and its parents whose type is assignable to the element type
+// of t, in the order of assignable. A type bound at several levels
+// contributes the value lookup resolves it to.
+func (i *injector) group(t reflect.Type, c *call) (reflect.Value, error) {
+	vals := reflect.MakeSlice(t, 0, 0)
+	for _, typ := range i.assignable(t.Elem()) {
+		val, err := i.lookup(typ, c)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		vals = reflect.Append(vals, val)
+	}
+	return vals, nil
+}
+
+// GetAll returns every value bound in the injector and its parents whose
+// type is assignable to t, for framework code fanning out over all the
+// registered components of a kind. Values are ordered by the injector
+// they are bound in, the injector itself first, then by type name. A type
+// bound at several levels contributes the value Get returns for it, and
+// values whose provider fails are left out.
+func (i *injector) GetAll(t reflect.Type) []reflect.Value {
+	var vals []reflect.Value
+	for _, typ := range i.assignable(t) {
+		if val, err := i.resolve(typ); err == nil {
+			vals = append(vals, val)
+		}
+	}
+	return vals
+}
+
+// member is a binding of a group.
+type member struct {
+	typ reflect.Type
+	e   *binding
+}
+
+// Group returns a BindOption adding the binding to the named groups
+// instead of binding its type, so that every member of a group, e.g. all
+// the HTTP handlers of a service, is resolved collectively into a slice
+// field tagged `inject:"group=handlers"` whatever their types. Members of
+// the same type do not replace each other.
+func Group(names ...string) BindOption {
+	return func(e *binding) {
+		e.groups = append(e.groups, names...)
+	}
+}
+
+// Priority returns a BindOption setting the priority of the binding within
+// its groups. Members of a group are ordered by ascending priority, so
+// that a migration with priority -10 runs before those with the default
+// priority 0; members of the same priority keep the order of members.
+func Priority(n int) BindOption {
+	return func(e *binding) {
+		e.priority = n
+	}
+}
+
+// members returns the members of the named group in the injector and its
+// parents, ordered by priority, then the injector first and in the order
+// they were registered.
+func (i *injector) members(name string, c *call) []groupMember {
+	all := i.unordered(name, c)
+	sort.SliceStable(all, func(a, b int) bool {
+		return all[a].e.priority < all[b].e.priority
+	})
+	return all
+}
+
+// unordered returns the members of the named group in the injector and its
+// parents, the injector first, each in the order they were registered.
+// Members of a parent are left out when the delegation policy or the
+// parent filter keep lookup from asking the parent for their type.
+func (i *injector) unordered(name string, c *call) []groupMember {
+	var all []groupMember
+	for _, m := range c.bindings(i).groups[name] {
+		all = append(all, groupMember{m, i})
+	}
+	for _, p := range c.bindings(i).parents {
+		pi, ok := p.inj.(*injector)
+		if !ok {
+			continue
+		}
+		for _, m := range pi.unordered(name, c) {
+			if i.delegates(m.typ) && p.allows(m.typ) {
+				all = append(all, m)
+			}
+		}
+	}
+	return all
+}
+
+// groupMember is a member along with the injector it is registered in.
+type groupMember struct {
+	member
+	owner *injector
+}
+
+// value resolves the value of the member as part of c.
+func (m groupMember) value(c *call) (reflect.Value, error) {
+	if m.e.provider == nil {
+		return m.e.mapped(), nil
+	}
+	return m.owner.provide(m.typ, m.e, c)
+}
+
+// groupSlice resolves a slice of type t holding the members of the named
+// group whose type is assignable to the element type of t.
+func (i *injector) groupSlice(name string, t reflect.Type, c *call) (reflect.Value, error) {
+	vals := reflect.MakeSlice(t, 0, 0)
+	for _, m := range i.members(name, c) {
+		if !m.typ.AssignableTo(t.Elem()) {
+			continue
+		}
+		val, err := m.value(c)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		vals = reflect.Append(vals, val)
+	}
+	return vals, nil
+}
+
+// InvokeGroup invokes every function registered in the named group, see
+// Group, with arguments injected from the injector, e.g. to run the
+// "migrations" or "shutdown" functions of every module. Functions are
+// invoked in the order of the members of the group, and a failure does not
+// prevent the following functions from running.
+// Returns an error joining the injection failures, the errors returned by
+// the functions as their last value and the members that are not
+// functions.
+func (i *injector) InvokeGroup(name string) error {
+	var errs []error
+	c := newCall(i)
+	for _, m := range i.members(name, c) {
+		fn, err := m.value(c)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if fn.Kind() == reflect.Interface {
+			fn = fn.Elem()
+		}
+		if fn.Kind() != reflect.Func {
+			errs = append(errs, fmt.Errorf("Member %v of group %q is not a function", m.typ, name))
+			continue
+		}
+		out, err := i.invoke(fn.Interface(), c, nil)
+		if err == nil {
+			err = lastError(out)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
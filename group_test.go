@@ -0,0 +1,154 @@
+package inject_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type Plugin interface {
+	Name() string
+}
+
+type alpha struct{}
+
+func (alpha) Name() string { return "alpha" }
+
+type beta struct{}
+
+func (*beta) Name() string { return "beta" }
+
+type gamma struct{}
+
+func (gamma) Name() string { return "gamma" }
+
+func Test_InjectorGroupField(t *testing.T) {
+	parent := inject.New()
+	parent.Map(gamma{}).Map(alpha{})
+	injector := inject.New()
+	injector.SetParent(parent)
+	injector.Provide(func() *beta { return &beta{} })
+	injector.Map("unrelated")
+
+	s := struct {
+		Plugins []Plugin `inject:"group"`
+	}{}
+	expect(t, injector.Apply(&s), nil)
+	expect(t, len(s.Plugins), 3)
+	expect(t, s.Plugins[0].Name(), "beta")
+	expect(t, s.Plugins[1].Name(), "alpha")
+	expect(t, s.Plugins[2].Name(), "gamma")
+	expect(t, injector.Validate(&s), nil)
+
+	empty := struct {
+		Names []error `inject:"group"`
+	}{}
+	expect(t, injector.Apply(&empty), nil)
+	expect(t, len(empty.Names), 0)
+}
+
+func Test_InjectorGetAll(t *testing.T) {
+	parent := inject.New()
+	parent.Map(gamma{})
+	injector := inject.New()
+	injector.SetParent(parent)
+	injector.Map(alpha{}).Map(42)
+
+	vals := injector.GetAll(inject.InterfaceOf((*Plugin)(nil)))
+	expect(t, len(vals), 2)
+	expect(t, vals[0].Interface().(Plugin).Name(), "alpha")
+	expect(t, vals[1].Interface().(Plugin).Name(), "gamma")
+	expect(t, len(injector.GetAll(reflect.TypeOf(""))), 0)
+}
+
+func Test_InjectorGroupBindings(t *testing.T) {
+	parent := inject.New()
+	parent.MapWith(alpha{}, inject.Group("plugins"))
+	injector := inject.New()
+	injector.SetParent(parent)
+	injector.MapWith(gamma{}, inject.Group("plugins"))
+	injector.MapToWith(gamma{}, (*Plugin)(nil), inject.Group("plugins", "extra"))
+	injector.Provide(func() *beta { return &beta{} }, inject.Group("plugins"))
+
+	s := struct {
+		Plugins []Plugin `inject:"group=plugins"`
+		Extra   []Plugin `inject:"group=extra"`
+		None    []Plugin `inject:"group=none"`
+	}{}
+	expect(t, injector.Apply(&s), nil)
+	expect(t, len(s.Plugins), 4)
+	expect(t, s.Plugins[0].Name(), "gamma")
+	expect(t, s.Plugins[2].Name(), "beta")
+	expect(t, s.Plugins[3].Name(), "alpha")
+	expect(t, len(s.Extra), 1)
+	expect(t, len(s.None), 0)
+	expect(t, injector.Validate(&s), nil)
+
+	_, err := injector.Invoke(func(gamma) {})
+	refute(t, err, nil)
+}
+
+func Test_InjectorInvokeGroup(t *testing.T) {
+	var ran []string
+	injector := inject.New()
+	injector.Map("db")
+	injector.MapWith(func(s string) { ran = append(ran, "first:"+s) }, inject.Group("migrations"))
+	injector.MapWith(func() error { ran = append(ran, "second"); return errors.New("boom") }, inject.Group("migrations"))
+	injector.MapWith(func(int) { ran = append(ran, "unresolvable") }, inject.Group("migrations"))
+	injector.MapWith(func() error { ran = append(ran, "third"); return nil }, inject.Group("migrations"))
+	injector.MapWith(42, inject.Group("migrations"))
+
+	err := injector.InvokeGroup("migrations")
+	refute(t, err, nil)
+	expect(t, strings.Join(ran, " "), "first:db second third")
+	expect(t, strings.Contains(err.Error(), "boom"), true)
+	expect(t, errors.Is(err, inject.ErrNotFound), true)
+	expect(t, strings.Contains(err.Error(), "not a function"), true)
+
+	expect(t, injector.InvokeGroup("empty"), nil)
+}
+
+func Test_InjectorGroupPriority(t *testing.T) {
+	var ran []string
+	parent := inject.New()
+	parent.MapWith(func() { ran = append(ran, "schema") }, inject.Group("migrations"), inject.Priority(-10))
+	parent.MapWith(func() { ran = append(ran, "cleanup") }, inject.Group("migrations"), inject.Priority(10))
+	injector := inject.New()
+	injector.SetParent(parent)
+	injector.MapWith(func() { ran = append(ran, "data") }, inject.Group("migrations"))
+	injector.MapWith(func() { ran = append(ran, "index") }, inject.Group("migrations"))
+
+	expect(t, injector.InvokeGroup("migrations"), nil)
+	expect(t, strings.Join(ran, " "), "schema data index cleanup")
+}
+
+func Test_InjectorGroupParentFilters(t *testing.T) {
+	host := inject.New()
+	host.Map(alpha{}).Map(gamma{})
+	host.MapWith(alpha{}, inject.Group("plugins"))
+	host.MapWith(gamma{}, inject.Group("plugins"))
+	plugin := inject.New()
+	plugin.AddParent(host, inject.Types(reflect.TypeOf(alpha{})))
+
+	s := struct {
+		All     []Plugin `inject:"group"`
+		Plugins []Plugin `inject:"group=plugins"`
+	}{}
+	expect(t, plugin.Apply(&s), nil)
+	expect(t, len(s.All), 1)
+	expect(t, s.All[0].Name(), "alpha")
+	expect(t, len(s.Plugins), 1)
+	expect(t, s.Plugins[0].Name(), "alpha")
+	expect(t, len(plugin.GetAll(inject.InterfaceOf((*Plugin)(nil)))), 1)
+
+	whitelisted := inject.New(inject.Delegation(inject.Whitelist, reflect.TypeOf(gamma{})))
+	whitelisted.SetParent(host)
+	expect(t, whitelisted.Apply(&s), nil)
+	expect(t, len(s.All), 1)
+	expect(t, s.All[0].Name(), "gamma")
+	expect(t, len(s.Plugins), 1)
+	expect(t, s.Plugins[0].Name(), "gamma")
+}
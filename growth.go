@@ -0,0 +1,52 @@
+package inject
+
+import "reflect"
+
+// MaxBindings returns an Option calling exceeded once the number of
+// bindings of the injector grows above n, which catches per-request code
+// mapping into a long-lived injector by mistake and leaking memory.
+// exceeded receives the number of bindings and the type just bound; a nil
+// exceeded sends a warning to the logger of the injector instead, see
+// WithLogger. The binding is kept, since Map cannot fail. exceeded is
+// called again every time the number of bindings doubled since the last
+// call, so that a leak does not flood the logs. Named bindings and the
+// members of groups count as bindings, replacing a binding does not.
+// It panics if n is not positive.
+func MaxBindings(n int, exceeded func(count int, t reflect.Type)) Option {
+	if n <= 0 {
+		panic("Called inject.MaxBindings with a limit that is not positive")
+	}
+	return func(i *injector) {
+		i.maxBindings = n
+		i.exceeded = exceeded
+	}
+}
+
+// count returns the number of bindings in b.
+func (b *bindings) count() int {
+	n := b.entries.size + len(b.named)
+	for _, members := range b.groups {
+		n += len(members)
+	}
+	return n
+}
+
+// grown is called by update, with the lock held, once t has been bound in
+// b as e. It returns the function reporting that the limit set with MaxBindings
+// is exceeded, to be called once the lock is released, if it is.
+func (i *injector) grown(b *bindings, t reflect.Type, e *binding) func() {
+	if i.maxBindings == 0 {
+		return nil
+	}
+	count := b.count()
+	if count <= i.maxBindings || count <= i.reported*2 {
+		return nil
+	}
+	i.reported = count
+	if i.exceeded != nil {
+		return func() { i.exceeded(count, t) }
+	}
+	return func() {
+		i.warnf("%d bindings exceed the limit of %d, the last one for type %v registered at %s", count, i.maxBindings, t, e.source())
+	}
+}
@@ -0,0 +1,46 @@
+package inject_test
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorMaxBindings(t *testing.T) {
+	var counts []int
+	var last reflect.Type
+	injector := inject.New(inject.MaxBindings(2, func(n int, t reflect.Type) {
+		counts = append(counts, n)
+		last = t
+	}))
+	injector.Map("a").Map(1)
+	injector.Map("b")
+	expect(t, len(counts), 0)
+
+	injector.Map(1.5)
+	expect(t, len(counts), 1)
+	expect(t, counts[0], 3)
+	expect(t, last, reflect.TypeOf(1.5))
+
+	for n := 0; n < 4; n++ {
+		injector.MapWith(n, inject.Named(strings.Repeat("n", n+1)))
+	}
+	expect(t, len(counts), 2)
+	expect(t, counts[1], 7)
+	expect(t, injector.Get(reflect.TypeOf(1.5)).Float(), 1.5)
+}
+
+func Test_InjectorMaxBindingsWarning(t *testing.T) {
+	var buf bytes.Buffer
+	parent := inject.New(inject.MaxBindings(1, nil), inject.WithLogger(log.New(&buf, "", 0)))
+	child := parent.Child()
+	child.Map("a")
+	expect(t, buf.Len(), 0)
+
+	child.Map(1)
+	expect(t, strings.Contains(buf.String(), "inject: 2 bindings exceed the limit of 1, the last one for type int registered at growth_test.go:"), true)
+}
@@ -0,0 +1,202 @@
+package inject
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+)
+
+// Debug returns an Option enabling the development checks of the
+// injector, such as the detection of mutated Immutable bindings. The checks
+// are skipped entirely when Debug is not set.
+func Debug() Option {
+	return func(i *injector) {
+		i.debug = true
+	}
+}
+
+// Immutable returns a BindOption marking the bound value as owned by its
+// binding: consumers may read it but must not change it. In Debug mode,
+// Invoke panics when the function it called left the value, or anything it
+// points to, different from the state it was handed out in.
+// Go cannot intercept method calls at run time, so the mutation is only
+// detected once the function returned, not when it happens: the panic
+// names the function, where it is declared, the argument or field the
+// value was injected into and the path of the first change, e.g. .Name,
+// to lead to the offending code. The value is compared field by field
+// against a deep copy taken at every resolution, funcs and chans by
+// identity since they are not copied.
+func Immutable() BindOption {
+	return func(e *binding) {
+		e.immutable = true
+	}
+}
+
+// guard is an Immutable value handed out during a call.
+type guard struct {
+	typ      reflect.Type
+	val      reflect.Value
+	snapshot reflect.Value
+	// e is the binding the value was resolved from, consumer what the
+	// value was injected into.
+	e        *binding
+	consumer Target
+}
+
+// watch records val, resolved for t, if it has to be checked for mutations.
+func (i *injector) watch(t reflect.Type, e *binding, val reflect.Value, c *call) {
+	if i.debug && e.immutable {
+		c.guards = append(c.guards, guard{t, val, deepCopy(val), e, c.target()})
+	}
+}
+
+// verify panics if one of the values guarded since the call had from
+// guards was changed by fn.
+func (c *call) verify(from int, fn reflect.Value) {
+	for _, g := range c.guards[from:] {
+		if !equal(g.val, g.snapshot) {
+			panic(fmt.Sprintf("inject: immutable %v bound at %s was mutated by %s, declared at %s, once injected as %v: %v%s changed; the mutation is detected after the function returned",
+				g.typ, g.e.source(), funcName(fn), funcSource(fn), g.consumer, g.typ, changed(g.snapshot, g.val)))
+		}
+	}
+}
+
+// funcSource returns the file:line the function fn is declared at.
+func funcSource(fn reflect.Value) string {
+	if f := runtime.FuncForPC(fn.Pointer()); f != nil {
+		file, line := f.FileLine(f.Entry())
+		return fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	return "?"
+}
+
+// changed returns the path from b to the first part of b that differs
+// from a, a copy of b made by deepCopy, e.g. ".Name" or "[2]", or an empty
+// path when b itself differs.
+func changed(a, b reflect.Value) string {
+	if a.Kind() != b.Kind() || a.Type() != b.Type() {
+		return ""
+	}
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return ""
+		}
+		return changed(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for n := 0; n < a.NumField(); n++ {
+			if !equal(a.Field(n), b.Field(n)) {
+				return "." + a.Type().Field(n).Name + changed(a.Field(n), b.Field(n))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return ""
+		}
+		for n := 0; n < a.Len(); n++ {
+			if !equal(a.Index(n), b.Index(n)) {
+				return fmt.Sprintf("[%d]", n) + changed(a.Index(n), b.Index(n))
+			}
+		}
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return ""
+		}
+		for _, k := range a.MapKeys() {
+			if bv := b.MapIndex(k); !bv.IsValid() || !equal(a.MapIndex(k), bv) {
+				return fmt.Sprintf("[%v]", k) + changed(a.MapIndex(k), bv)
+			}
+		}
+	}
+	return ""
+}
+
+// equal reports whether a and b are deeply equal, unexported fields
+// included. Unlike reflect.DeepEqual, which never holds for funcs but nil
+// ones, funcs and chans are equal when they are the same func or chan.
+func equal(a, b reflect.Value) bool {
+	return same(a, b, make(map[visit]bool))
+}
+
+// visit is a pair of references compared by same, which are taken to be
+// equal when compared again to terminate on cyclic data.
+type visit struct {
+	a, b uintptr
+	typ  reflect.Type
+}
+
+func same(a, b reflect.Value, seen map[visit]bool) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		if a.Kind() != reflect.Slice && a.Pointer() == b.Pointer() {
+			return true
+		}
+		v := visit{a.Pointer(), b.Pointer(), a.Type()}
+		if seen[v] {
+			return true
+		}
+		seen[v] = true
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr:
+		return same(a.Elem(), b.Elem(), seen)
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return same(a.Elem(), b.Elem(), seen)
+	case reflect.Struct:
+		for n := 0; n < a.NumField(); n++ {
+			if !same(a.Field(n), b.Field(n), seen) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for n := 0; n < a.Len(); n++ {
+			if !same(a.Index(n), b.Index(n), seen) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for _, k := range a.MapKeys() {
+			if bv := b.MapIndex(k); !bv.IsValid() || !same(a.MapIndex(k), bv, seen) {
+				return false
+			}
+		}
+		return true
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	case reflect.String:
+		return a.String() == b.String()
+	}
+	return true
+}
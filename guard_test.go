@@ -0,0 +1,82 @@
+package inject_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorImmutable(t *testing.T) {
+	injector := inject.New(inject.Debug())
+	injector.MapWith(&Config{Name: "shared"}, inject.Immutable())
+
+	_, err := injector.Invoke(func(c *Config) {
+		expect(t, c.Name, "shared")
+	})
+	expect(t, err, nil)
+
+	defer func() {
+		r := recover()
+		refute(t, r, nil)
+		msg := r.(string)
+		expect(t, strings.Contains(msg, "immutable *inject_test.Config bound at guard_test.go:"), true)
+		expect(t, strings.Contains(msg, "#0: *inject_test.Config.Name changed"), true)
+	}()
+	injector.Invoke(func(c *Config) {
+		c.Name = "changed"
+	})
+}
+
+func Test_InjectorImmutableWithoutDebug(t *testing.T) {
+	injector := inject.New()
+	injector.MapWith(&Config{Name: "shared"}, inject.Immutable())
+
+	_, err := injector.Invoke(func(c *Config) {
+		c.Name = "changed"
+	})
+	expect(t, err, nil)
+}
+
+type guardedSettings struct {
+	Limits map[string][]int
+}
+
+func Test_InjectorImmutableChangedPath(t *testing.T) {
+	injector := inject.New(inject.Debug())
+	injector.MapWith(&guardedSettings{map[string][]int{"rps": {10, 20}}}, inject.Immutable())
+
+	defer func() {
+		r := recover()
+		refute(t, r, nil)
+		expect(t, strings.Contains(r.(string), ": *inject_test.guardedSettings.Limits[rps][1] changed;"), true)
+	}()
+	injector.Invoke(func(s *guardedSettings) {
+		s.Limits["rps"][1] = 30
+	})
+}
+
+type guardedHooks struct {
+	Name    string
+	OnClose func() error
+	Done    chan struct{}
+}
+
+func Test_InjectorImmutableFuncField(t *testing.T) {
+	injector := inject.New(inject.Debug())
+	injector.MapWith(&guardedHooks{"hooks", func() error { return nil }, make(chan struct{})}, inject.Immutable())
+
+	_, err := injector.Invoke(func(h *guardedHooks) {
+		expect(t, h.Name, "hooks")
+	})
+	expect(t, err, nil)
+
+	defer func() {
+		r := recover()
+		refute(t, r, nil)
+		expect(t, strings.Contains(r.(string), ": *inject_test.guardedHooks.OnClose changed;"), true)
+	}()
+	injector.Invoke(func(h *guardedHooks) {
+		h.OnClose = func() error { return nil }
+	})
+}
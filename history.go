@@ -0,0 +1,88 @@
+package inject
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Resolution is a dependency resolved by Invoke, Apply, Construct or
+// Populate, as recorded with the History option.
+type Resolution struct {
+	Type reflect.Type
+	// Requester is the function, provider or struct type the dependency
+	// was resolved for.
+	Requester string
+	// Err is the error of a failed resolution.
+	Err      error
+	Duration time.Duration
+	Time     time.Time
+}
+
+// ring is a bounded log of the latest resolutions.
+type ring struct {
+	mu   sync.Mutex
+	buf  []Resolution
+	next int
+	full bool
+}
+
+func newRing(n int) *ring {
+	return &ring{buf: make([]Resolution, n)}
+}
+
+// History returns an Option keeping the last n resolutions of the
+// injector in memory, so that a crash handler can dump the wiring events
+// that led to a failure with Recent. A resolution is recorded by the
+// injector the call started from, or by the injector of the provider
+// whose arguments are resolved.
+// It panics if n is not positive.
+func History(n int) Option {
+	if n <= 0 {
+		panic("Called inject.History with a size that is not positive")
+	}
+	return func(i *injector) {
+		i.history = newRing(n)
+	}
+}
+
+// started returns the start of a resolution remember records, the zero
+// time unless the History option is set, so that resolutions do not read
+// the clock for nothing.
+func (i *injector) started() time.Time {
+	if i.history == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// remember records the resolution of t for requester that started at
+// start, when the History option is set.
+func (i *injector) remember(requester func() string, t reflect.Type, start time.Time, err error) {
+	if i.history == nil {
+		return
+	}
+	r := Resolution{t, requester(), err, time.Since(start), start}
+
+	h := i.history
+	h.mu.Lock()
+	h.buf[h.next] = r
+	h.next = (h.next + 1) % len(h.buf)
+	h.full = h.full || h.next == 0
+	h.mu.Unlock()
+}
+
+// Recent returns the resolutions recorded with the History option, oldest
+// first, or nil if the option is not set.
+func (i *injector) Recent() []Resolution {
+	h := i.history
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.full {
+		return append([]Resolution(nil), h.buf[:h.next]...)
+	}
+	return append(append([]Resolution(nil), h.buf[h.next:]...), h.buf[:h.next]...)
+}
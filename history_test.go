@@ -0,0 +1,40 @@
+package inject_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorHistory(t *testing.T) {
+	injector := inject.New(inject.History(3))
+	injector.Map("value")
+	expect(t, len(injector.(inject.Diagnostics).Recent()), 0)
+
+	_, err := injector.Invoke(func(string) {})
+	expect(t, err, nil)
+	recent := injector.(inject.Diagnostics).Recent()
+	expect(t, len(recent), 1)
+	expect(t, recent[0].Type, reflect.TypeOf(""))
+	expect(t, strings.Contains(recent[0].Requester, "Test_InjectorHistory"), true)
+	expect(t, recent[0].Err, nil)
+
+	s := struct {
+		S string `inject`
+		N int    `inject`
+	}{}
+	refute(t, injector.Apply(&s), nil)
+	var n int
+	refute(t, injector.Populate(&n), nil)
+
+	recent = injector.(inject.Diagnostics).Recent()
+	expect(t, len(recent), 3)
+	expect(t, recent[0].Type, reflect.TypeOf(""))
+	expect(t, recent[1].Type, reflect.TypeOf(0))
+	refute(t, recent[1].Err, nil)
+	expect(t, recent[2].Requester, "Populate")
+
+	expect(t, inject.New().(inject.Diagnostics).Recent() == nil, true)
+}
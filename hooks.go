@@ -0,0 +1,50 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// OnCreate returns a BindOption calling fn with every value built by the
+// provider of the binding, before the value is handed out. An error
+// returned by fn fails the resolution, and a memoized provider is invoked
+// again on the next one.
+// OnCreate has no effect on mapped values.
+func OnCreate(fn func(val interface{}) error) BindOption {
+	return func(e *binding) {
+		e.onCreate = append(e.onCreate, fn)
+	}
+}
+
+// OnDestroy returns a BindOption calling fn with every value built by the
+// provider of the binding when the injector owning the value is disposed:
+// the injector of the binding for memoized providers, the scope returned
+// by Enter the resolution started from for transient ones. Transient
+// values resolved from any other injector are not tracked, so that a
+// long-lived injector does not hold on to every value it ever built.
+// Hooks run before the value is closed, in the reverse order of
+// construction like every release.
+// OnDestroy has no effect on mapped values.
+func OnDestroy(fn func(val interface{}) error) BindOption {
+	return func(e *binding) {
+		e.onDestroy = append(e.onDestroy, fn)
+	}
+}
+
+// created runs the OnCreate hooks of e for the value val built for t.
+func created(t reflect.Type, e *binding, val reflect.Value) error {
+	for _, fn := range e.onCreate {
+		if err := fn(val.Interface()); err != nil {
+			return fmt.Errorf("OnCreate hook for type %v failed: %w", t, err)
+		}
+	}
+	return nil
+}
+
+// destroyed registers the OnDestroy hooks of e for val with the injector.
+func (i *injector) destroyed(e *binding, val reflect.Value) {
+	for _, fn := range e.onDestroy {
+		fn, v := fn, val.Interface()
+		i.OnDispose(func() error { return fn(v) })
+	}
+}
@@ -0,0 +1,79 @@
+package inject_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorBindingHooks(t *testing.T) {
+	var events []string
+	injector := inject.New()
+	injector.Provide(func() io.Closer { return &closer{"close", &events} },
+		inject.OnCreate(func(v interface{}) error {
+			events = append(events, "create")
+			return nil
+		}),
+		inject.OnDestroy(func(v interface{}) error {
+			_, ok := v.(*closer)
+			expect(t, ok, true)
+			events = append(events, "destroy")
+			return nil
+		}))
+
+	_, err := injector.Invoke(func(io.Closer) {})
+	expect(t, err, nil)
+	_, err = injector.Invoke(func(io.Closer) {})
+	expect(t, err, nil)
+	expect(t, strings.Join(events, " "), "create")
+
+	expect(t, injector.Dispose(), nil)
+	expect(t, strings.Join(events, " "), "create destroy close")
+}
+
+func Test_InjectorBindingHooksFailure(t *testing.T) {
+	boom := errors.New("boom")
+	calls := 0
+	injector := inject.New()
+	injector.Provide(func() int { calls++; return calls },
+		inject.OnCreate(func(v interface{}) error {
+			if v.(int) == 1 {
+				return boom
+			}
+			return nil
+		}))
+
+	_, err := injector.Invoke(func(int) {})
+	expect(t, errors.Is(err, boom), true)
+
+	var n int
+	expect(t, injector.Populate(&n), nil)
+	expect(t, n, 2)
+}
+
+func Test_InjectorBindingHooksTransient(t *testing.T) {
+	destroyed := 0
+	injector := inject.New()
+	injector.Provide(func() int { return 1 }, inject.Transient(),
+		inject.OnDestroy(func(interface{}) error {
+			destroyed++
+			return nil
+		}))
+
+	for n := 0; n < 3; n++ {
+		_, err := injector.Invoke(func(int) {})
+		expect(t, err, nil)
+	}
+	expect(t, injector.BuildReport().Disposers, 0)
+	expect(t, injector.Dispose(), nil)
+	expect(t, destroyed, 0)
+
+	req := injector.Enter(inject.Request)
+	_, err := req.Invoke(func(int, int) {})
+	expect(t, err, nil)
+	expect(t, req.Dispose(), nil)
+	expect(t, destroyed, 2)
+}
@@ -0,0 +1,144 @@
+package inject
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Implementations returns an Option resolving an interface type that is
+// not bound from a bound type implementing it. When several bound types
+// implement the interface, the most specific one is selected: a binding
+// declared for another interface, e.g. with MapTo, beats an incidental
+// concrete implementer, and among bindings of the same sort the one with
+// the fewest methods, that is the closest to the requested interface,
+// wins. The interface is reported as not found when no single binding is
+// the most specific.
+func Implementations() Option {
+	return func(i *injector) {
+		i.implementations = true
+	}
+}
+
+// implementation returns the most specific bound type implementing the
+// interface type t, when the Implementations option is set or when t is an
+// anonymous interface, such as interface{ Log(string) }, which no binding
+// can be declared for short of repeating the literal. The empty interface
+// is never resolved this way.
+func (i *injector) implementation(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Interface || (!i.implementations && (t.Name() != "" || t.NumMethod() == 0)) {
+		return nil, false
+	}
+	var best reflect.Type
+	tie := false
+	for _, c := range i.implementers(t) {
+		if c == best {
+			continue
+		}
+		switch {
+		case best == nil || moreSpecific(c, best):
+			best, tie = c, false
+		case !moreSpecific(best, c):
+			tie = true
+		}
+	}
+	if best == nil || tie {
+		return nil, false
+	}
+	return best, true
+}
+
+// moreSpecific reports whether the candidate a is a better match than b.
+func moreSpecific(a, b reflect.Type) bool {
+	ai, bi := a.Kind() == reflect.Interface, b.Kind() == reflect.Interface
+	if ai != bi {
+		return ai
+	}
+	return a.NumMethod() < b.NumMethod()
+}
+
+// implementers returns the types bound in the injector and its parent
+// chain implementing the interface type t, the injector first. The types
+// implementing t are indexed the first time t is looked up and then kept
+// up to date by bind, so that resolving an interface does not scan every
+// binding. Types the injector may not ask a parent for are left out.
+func (i *injector) implementers(t reflect.Type) []reflect.Type {
+	types, ok := i.snapshot().implementers[t]
+	if !ok {
+		i.update(func(b *bindings) {
+			if types, ok = b.implementers[t]; ok {
+				return
+			}
+			for _, c := range b.types() {
+				if c != t && implements(c, t) {
+					types = append(types, c)
+				}
+			}
+			if b.own(ownsImplementers) {
+				b.implementers = cloneMap(b.implementers)
+			}
+			b.implementers[t] = types
+		})
+	}
+
+	// appending must not write into the indexed slice
+	types = types[:len(types):len(types)]
+	for _, p := range i.snapshot().parents {
+		pi, ok := p.inj.(*injector)
+		if !ok {
+			continue
+		}
+		for _, c := range pi.implementers(t) {
+			if i.delegates(c) && p.allows(c) {
+				types = append(types, c)
+			}
+		}
+	}
+	return types
+}
+
+// indexImplementer adds t, just bound, to the implementers of the
+// interfaces it implements.
+func (b *bindings) indexImplementer(t reflect.Type) {
+	for iface, types := range b.implementers {
+		if t == iface || !implements(t, iface) {
+			continue
+		}
+		n := sort.Search(len(types), func(k int) bool {
+			return types[k].String() >= t.String()
+		})
+		if n < len(types) && types[n] == t {
+			continue
+		}
+		// copied since the slice is shared with older snapshots
+		indexed := make([]reflect.Type, 0, len(types)+1)
+		indexed = append(append(append(indexed, types[:n]...), t), types[n:]...)
+		if b.own(ownsImplementers) {
+			b.implementers = cloneMap(b.implementers)
+		}
+		b.implementers[iface] = indexed
+	}
+}
+
+type implementsKey struct {
+	typ, iface reflect.Type
+}
+
+// implementsCache memoizes implements. Types never change, so results are
+// kept for the lifetime of the program; they are bounded by the number of
+// bound types times the number of interfaces requested.
+var implementsCache sync.Map
+
+// implements reports whether t implements the interface type iface,
+// comparing their method sets only the first time the pair is checked, so
+// that resolving an interface that is not bound does not compare every
+// bound type again on each request.
+func implements(t, iface reflect.Type) bool {
+	key := implementsKey{t, iface}
+	if ok, found := implementsCache.Load(key); found {
+		return ok.(bool)
+	}
+	ok := t.Implements(iface)
+	implementsCache.Store(key, ok)
+	return ok
+}
@@ -0,0 +1,89 @@
+package inject_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorImplementations(t *testing.T) {
+	injector := inject.New(inject.Implementations())
+	injector.Map(strings.NewReader("concrete"))
+	_, err := injector.Invoke(func(r io.Reader) {
+		b, _ := io.ReadAll(r)
+		expect(t, string(b), "concrete")
+	})
+	expect(t, err, nil)
+
+	var rc io.ReadCloser = io.NopCloser(strings.NewReader("declared"))
+	injector.MapTo(rc, (*io.ReadCloser)(nil))
+	_, err = injector.Invoke(func(r io.Reader) {
+		b, _ := io.ReadAll(r)
+		expect(t, string(b), "declared")
+	})
+	expect(t, err, nil)
+	expect(t, injector.Validate(func(io.Reader) {}), nil)
+
+	strict := inject.New()
+	strict.Map(strings.NewReader("concrete"))
+	_, err = strict.Invoke(func(io.Reader) {})
+	refute(t, err, nil)
+}
+
+func Test_InjectorImplementationsAmbiguous(t *testing.T) {
+	injector := inject.New(inject.Implementations())
+	injector.Map(alpha{}).Map(gamma{})
+	_, err := injector.Invoke(func(Plugin) {})
+	refute(t, err, nil)
+
+	injector.MapTo(gamma{}, (*Plugin)(nil))
+	_, err = injector.Invoke(func(p Plugin) { expect(t, p.Name(), "gamma") })
+	expect(t, err, nil)
+}
+
+func Test_InjectorImplementationsIndex(t *testing.T) {
+	parent := inject.New(inject.Implementations())
+	injector := parent.Child()
+	_, err := injector.Invoke(func(io.Writer) {})
+	refute(t, err, nil)
+
+	var buf strings.Builder
+	parent.Map(&buf)
+	_, err = injector.Invoke(func(w io.Writer) { io.WriteString(w, "parent") })
+	expect(t, err, nil)
+	expect(t, buf.String(), "parent")
+
+	var local strings.Builder
+	isolated := inject.New(inject.Implementations())
+	isolated.Map(3)
+	_, err = isolated.Invoke(func(io.Writer) {})
+	refute(t, err, nil)
+	isolated.Map(&local)
+	_, err = isolated.Invoke(func(w io.Writer) { io.WriteString(w, "local") })
+	expect(t, err, nil)
+	expect(t, local.String(), "local")
+}
+
+type printer struct{ lines *[]string }
+
+func (p printer) Log(s string) { *p.lines = append(*p.lines, s) }
+
+func Test_InjectorAnonymousInterface(t *testing.T) {
+	var lines []string
+	injector := inject.New()
+	injector.Map(printer{&lines})
+
+	_, err := injector.Invoke(func(l interface{ Log(string) }) { l.Log("hello") })
+	expect(t, err, nil)
+	expect(t, strings.Join(lines, ","), "hello")
+	expect(t, injector.Validate(func(interface{ Log(string) }) {}), nil)
+
+	_, err = injector.Invoke(func(interface{ Close() error }) {})
+	refute(t, err, nil)
+	_, err = injector.Invoke(func(interface{}) {})
+	refute(t, err, nil)
+	_, err = injector.Invoke(func(io.Writer) {})
+	refute(t, err, nil)
+}
@@ -2,8 +2,12 @@
 package inject
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"sync"
+	"sync/atomic"
 )
 
 // Injector represents an interface for mapping and injecting dependencies into structs
@@ -16,6 +20,102 @@ type Injector interface {
 	// dependency in its Type map it will check its parent before returning an
 	// error.
 	SetParent(Injector)
+	// Child returns a new injector whose parent is the injector, configured
+	// like the injector and then by the given options.
+	Child(...Option) Injector
+	// Enter returns a child injector standing for the given scope, whose
+	// Dispose tears down every value built inside it.
+	Enter(Scope, ...Option) *ScopeHandle
+	// AddParent appends a parent that is asked, after the previous ones,
+	// for the types the filter accepts. A nil filter accepts every type.
+	AddParent(Injector, func(reflect.Type) bool)
+	// SetScope names the scope the injector stands for, see GetScoped.
+	SetScope(Scope)
+	// Scope returns the scope set with SetScope.
+	Scope() Scope
+	// OnDispose registers a function to be called by Dispose.
+	OnDispose(func() error)
+	// Invalidate forgets the value built by the memoized provider of the
+	// type, so that the next resolution builds it again.
+	Invalidate(reflect.Type) error
+	// Alias makes the binding of the first type resolvable as the second
+	// type too, sharing its value or provider.
+	Alias(reflect.Type, reflect.Type) error
+	// Dispose releases the functions registered with OnDispose and the
+	// values built by the providers of the injector implementing io.Closer.
+	Dispose() error
+	// InvokeGroup invokes every function of the named group and joins
+	// their errors.
+	InvokeGroup(string) error
+	// ForEach calls the function with every binding of the injector and
+	// its parents until it returns false.
+	ForEach(func(Binding) bool)
+	// Select returns the bindings walked by ForEach that the predicate
+	// accepts.
+	Select(func(Binding) bool) []Binding
+	// ExportTo maps the values the injector resolves for the given types
+	// into another injector.
+	ExportTo(Injector, ...reflect.Type) error
+	// Populate resolves the type each of its arguments points to and stores
+	// the resolved value through the pointer. Returns an error if any of the
+	// types cannot be resolved.
+	Populate(...interface{}) error
+	// Validate checks that the dependencies of the given functions and
+	// structs, and of every provider, can be resolved without calling them.
+	Validate(...interface{}) error
+	// Seal computes the values of the bindings registered with MapDeferred
+	// since the injector was last sealed.
+	Seal() error
+	// BuildReport reports how the application wired in the injector would
+	// be built, without calling any function or provider.
+	BuildReport(...interface{}) WiringReport
+	// ValidateStrict runs Validate and additionally rejects the unused or
+	// shadowed bindings selected by the Strictness level.
+	ValidateStrict(Strictness, ...interface{}) error
+	// Unused returns the mapped types that have not been resolved since the
+	// injector was created or ResetUsage was last called.
+	Unused() []reflect.Type
+	// ResetUsage starts a new recording window for Unused.
+	ResetUsage()
+	// Shadowed returns the types bound both in the injector and in its
+	// parent chain.
+	Shadowed() []reflect.Type
+	// Profile registers the function setting up the bindings of a named
+	// profile.
+	Profile(string, func(TypeMapper))
+	// Activate layers the bindings of the named profiles, in order, on top
+	// of the bindings of the injector.
+	Activate(...string) error
+	// ActivateFromEnv activates the comma separated profiles named by an
+	// environment variable.
+	ActivateFromEnv(string) error
+}
+
+// Diagnostics reports how an injector is used. The injectors returned by
+// New implement it, but it is no part of Injector so that the other
+// implementations of Injector do not have to, e.g.
+//
+//	stats := inj.(inject.Diagnostics).Stats()
+type Diagnostics interface {
+	// Stats returns the number of resolutions and the time of the last one
+	// of every bound type of the injector that has been resolved, see
+	// Statistics.
+	Stats() map[reflect.Type]Stat
+	// Recent returns the last resolutions recorded with the History
+	// option, oldest first.
+	Recent() []Resolution
+	// Graph returns the bindings of the injector and its parents along
+	// with the types their providers depend on.
+	Graph() Graph
+}
+
+// Warmer builds the values of the memoized providers of an injector ahead
+// of their first resolution. The injectors returned by New implement it,
+// like Diagnostics.
+type Warmer interface {
+	// Warm builds the values of every memoized provider of the injector,
+	// giving up once the context is done.
+	Warm(context.Context, ...WarmOption) error
 }
 
 // Applicator represents an interface for mapping dependencies to a struct.
@@ -24,6 +124,24 @@ type Applicator interface {
 	// that is tagged with 'inject'. Returns an error if the injection
 	// fails.
 	Apply(interface{}) error
+	// ApplyWithContext works like Apply but resolves the values carried by
+	// the context, see WithValues, before the bindings of the injector.
+	ApplyWithContext(context.Context, interface{}) error
+	// ExplainApply reports how each tagged field of the struct would be
+	// injected, without modifying it.
+	ExplainApply(interface{}) ([]Explanation, error)
+	// ApplyReport works like Apply and reports how each field it set was
+	// injected.
+	ApplyReport(interface{}) ([]Explanation, error)
+	// Construct allocates the struct pointed to by its argument and wires it
+	// completely: tagged fields are resolved from the Type map, unmapped
+	// struct dependencies are constructed recursively and PostConstruct is
+	// called on every value that implements PostConstructor.
+	Construct(interface{}) error
+	// ConstructWithContext works like Construct but resolves the values
+	// carried by the context first and stops building providers once the
+	// context is done.
+	ConstructWithContext(context.Context, interface{}) error
 }
 
 // Invoker represents an interface for calling functions via reflection.
@@ -33,6 +151,34 @@ type Invoker interface {
 	// a slice of reflect.Value representing the returned values of the function.
 	// Returns an error if the injection fails.
 	Invoke(interface{}) ([]reflect.Value, error)
+	// InvokeWithContext works like Invoke but resolves the values carried by
+	// the context, see WithValues, before the bindings of the injector.
+	InvokeWithContext(context.Context, interface{}) ([]reflect.Value, error)
+	// ExplainInvoke reports where each argument of the function would be
+	// resolved from, without calling it.
+	ExplainInvoke(interface{}) []Explanation
+	// InvokeTraced works like Invoke and also returns a trace of every
+	// lookup performed to resolve the arguments.
+	InvokeTraced(interface{}) ([]reflect.Value, []TraceStep, error)
+	// InvokeAsync resolves the arguments of the function and calls it on a
+	// new goroutine, returning a Future reporting its results.
+	InvokeAsync(interface{}) *Future
+	// InvokeConcurrently invokes the functions in parallel and returns
+	// once all of them returned, cancelling the others on the first
+	// failure.
+	InvokeConcurrently(context.Context, ...interface{}) error
+	// InvokeConcurrentlyJoined invokes the functions in parallel, lets
+	// every one of them return and joins their errors.
+	InvokeConcurrentlyJoined(context.Context, ...interface{}) error
+	// InvokeOverriding works like Invoke but passes the given values for
+	// the arguments at the positions they override.
+	InvokeOverriding(interface{}, ...Override) ([]reflect.Value, error)
+	// InvokePartial resolves the arguments of the function the injector
+	// can resolve and returns a function taking the others.
+	InvokePartial(interface{}) (interface{}, error)
+	// InvokeUsing works like Invoke but also consults the given injectors,
+	// in order, for the types the injector cannot resolve.
+	InvokeUsing(interface{}, ...Injector) ([]reflect.Value, error)
 }
 
 // TypeMapper represents an interface for mapping interface{} values based on type.
@@ -47,16 +193,101 @@ type TypeMapper interface {
 	// This makes it possible to directly map type arguments not possible to instantiate
 	// with reflect like unidirectional channels.
 	Set(reflect.Type, reflect.Value) TypeMapper
+	// Like Map, registering the binding with the given options.
+	MapWith(interface{}, ...BindOption) TypeMapper
+	// Like MapTo, registering the binding with the given options.
+	MapToWith(interface{}, interface{}, ...BindOption) TypeMapper
+	// Like Set, registering the binding with the given options.
+	SetWith(reflect.Type, reflect.Value, ...BindOption) TypeMapper
+	// Maps the first return type of the provider function to the value it
+	// returns. The provider is invoked with injected arguments the first time
+	// the type is requested and its result is reused afterwards.
+	Provide(interface{}, ...BindOption) TypeMapper
+	// Binds one of two values or providers depending on a condition that is
+	// evaluated with the injector the first time the type is requested.
+	BindIf(func(Injector) bool, interface{}, interface{}) TypeMapper
+	// Like BindIf, but binds the interface the last argument points to, which
+	// the two values or providers implement.
+	BindIfTo(func(Injector) bool, interface{}, interface{}, interface{}) TypeMapper
+	// Binds two values or providers and routes each resolution of their type
+	// to one of them as described by the Split.
+	BindSplit(Split, interface{}, interface{}) TypeMapper
+	// Binds the Type to the value a function computes with the injector
+	// once the injector is sealed, see Seal.
+	MapDeferred(reflect.Type, func(Injector) (interface{}, error), ...BindOption) TypeMapper
+	// Binds the Type to a live reference resolving it from another
+	// injector on every resolution.
+	MapRef(Injector, reflect.Type, ...BindOption) TypeMapper
 	// Returns the Value that is mapped to the current type. Returns a zeroed Value if
 	// the Type has not been mapped.
 	Get(reflect.Type) reflect.Value
+	// Returns the Value mapped to the Type as seen from the closest injector
+	// of the parent chain that has the given scope.
+	GetScoped(reflect.Type, Scope) reflect.Value
+	// Returns every Value bound in the injector or its parents whose type
+	// is assignable to the Type.
+	GetAll(reflect.Type) []reflect.Value
 }
 
 type injector struct {
-	values map[reflect.Type]reflect.Value
-	parent Injector
+	// current holds the published bindings, see update.
+	current atomic.Pointer[bindings]
+	// mu serializes writers of current and guards staged, disposers,
+	// deferred, fakes, profiles, active and copies.
+	mu sync.Mutex
+
+	// staged holds the bindings staged by stage, which holds staging.
+	staged  *bindings
+	staging sync.Mutex
+
+	// stats holds the *typeStats of the types of the Type map, see Stats
+	// and Unused.
+	stats     sync.Map
+	disposers []func() error
+	deferred  []deferred
+	fakes     map[reflect.Type]reflect.Value
+	// active are the profiles activated, in order.
+	active []string
+
+	delegation      DelegationPolicy
+	whitelist       map[reflect.Type]bool
+	profiles        map[string]func(TypeMapper)
+	logger          Logger
+	copies          map[reflect.Type]int
+	largeSize       uintptr
+	debug           bool
+	slots           chan struct{}
+	conversions     bool
+	implementations bool
+	strictApply     bool
+	labels          bool
+	maxBindings     int
+	exceeded        func(int, reflect.Type)
+	history         *ring
+	statistics      bool
+	policies        []Policy
+	banner          bool
+	// reported is the number of bindings last reported as exceeding
+	// maxBindings, guarded by mu.
+	reported int
+	// tag is the key of the struct tags injected, see TagName.
+	tag string
+	// presets are the presets of the options of the inject tag, see
+	// TagPreset.
+	presets *tagPresets
+	// entered is set on the injectors returned by Enter, which also close
+	// the values built by transient providers.
+	entered bool
 }
 
+// resolver is implemented by injectors that can report why a type could
+// not be resolved instead of only returning a zeroed Value.
+type resolver interface {
+	lookup(reflect.Type, *call) (reflect.Value, error)
+}
+
+var injectorType = reflect.TypeOf((*Injector)(nil)).Elem()
+
 // InterfaceOf dereferences a pointer to an Interface type.
 // It panics if value is not an pointer to an interface.
 func InterfaceOf(value interface{}) reflect.Type {
@@ -73,11 +304,14 @@ func InterfaceOf(value interface{}) reflect.Type {
 	return t
 }
 
-// New returns a new Injector.
-func New() Injector {
-	return &injector{
-		values: make(map[reflect.Type]reflect.Value),
+// New returns a new Injector configured with opts.
+func New(opts ...Option) Injector {
+	inj := &injector{}
+	inj.current.Store(&bindings{})
+	for _, opt := range opts {
+		opt(inj)
 	}
+	return inj
 }
 
 // Invoke attempts to call the interface{} provided as a function,
@@ -86,51 +320,171 @@ func New() Injector {
 // Returns an error if the injection fails.
 // It panics if f is not a function
 func (inj *injector) Invoke(f interface{}) ([]reflect.Value, error) {
+	return inj.invoke(f, newCall(inj), nil)
+}
+
+// invoke calls f with arguments resolved as part of c, except for those
+// fixed by their position.
+func (inj *injector) invoke(f interface{}, c *call, fixed map[int]reflect.Value) ([]reflect.Value, error) {
+	defer c.serialize()()
+	guards := len(c.guards)
+	in, err := inj.arguments(f, c, fixed)
+	if err != nil {
+		return nil, err
+	}
+
+	fn := reflect.ValueOf(f)
+	out := inj.run(fn, in, c)
+	c.verify(guards, fn)
+	return out, nil
+}
+
+// arguments resolves the arguments of the function f as part of c, except
+// for those fixed by their position.
+func (inj *injector) arguments(f interface{}, c *call, fixed map[int]reflect.Value) ([]reflect.Value, error) {
 	t := reflect.TypeOf(f)
 
 	var in = make([]reflect.Value, t.NumIn()) //Panic if t is not kind of Func
 	for i := 0; i < t.NumIn(); i++ {
+		if val, ok := fixed[i]; ok {
+			in[i] = val
+			continue
+		}
 		argType := t.In(i)
-		val := inj.Get(argType)
-		if !val.IsValid() {
-			return nil, fmt.Errorf("Value not found for type %v", argType)
+		start := inj.started()
+		val, err := inj.argument(f, i, c)
+		if !c.dry {
+			inj.remember(func() string { return funcName(reflect.ValueOf(f)) }, argType, start, err)
+		}
+		if err != nil {
+			name := funcName(reflect.ValueOf(f))
+			return nil, &InjectionError{name, fmt.Sprintf("#%d", i), argType, err}
 		}
 
 		in[i] = val
 	}
 
-	return reflect.ValueOf(f).Call(in), nil
+	return in, nil
+}
+
+// argument resolves the argument n of the function f as part of c.
+func (inj *injector) argument(f interface{}, n int, c *call) (reflect.Value, error) {
+	t := reflect.TypeOf(f)
+	if t.In(n) == callType {
+		// a provider synthesized by BindIf or BindSplit
+		return reflect.ValueOf(c), nil
+	}
+	if t.In(n) == targetType {
+		// the consumer of the value f builds, if f is a provider
+		return inj.lookup(targetType, c)
+	}
+	prev := c.consumer
+	defer func() { c.consumer = prev }()
+	c.consumer = consumer{owner: t, fn: f, index: n}
+	if err := inj.enforce(c); err != nil {
+		return reflect.Value{}, err
+	}
+	return inj.lookup(t.In(n), c)
 }
 
 // Maps dependencies in the Type map to each field in the struct
-// that is tagged with 'inject'.
-// Returns an error if the injection fails.
+// that is tagged with 'inject'. Fields may also be set from the
+// environment or from a default value, e.g.
+// `inject:"" env:"PORT" default:"8080"`. The tagged fields promoted from an
+// untagged embedded struct are set as well, so that a base embedded by
+// several structs is wired once; those promoted through a nil embedded
+// pointer are left alone.
+// Returns an error if the injection fails or if val is a struct passed by
+// value, whose fields cannot be set.
 func (inj *injector) Apply(val interface{}) error {
+	return inj.apply(val, newCall(inj))
+}
+
+// apply injects the tagged fields of val with values resolved as part of c.
+func (inj *injector) apply(val interface{}, c *call) error {
+	if inj.strictApply {
+		if err := applicable(val); err != nil {
+			return err
+		}
+	}
 	v := reflect.ValueOf(val)
+	if v.Kind() == reflect.Struct {
+		return byValue(v.Type())
+	}
 
 	for v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
 
 	if v.Kind() != reflect.Struct {
-		return nil // Should not panic here ?
+		return nil // see StrictApply
 	}
 
 	t := v.Type()
 
-	for i := 0; i < v.NumField(); i++ {
-		f := v.Field(i)
-		structField := t.Field(i)
-		if f.CanSet() && structField.Tag == "inject" {
-			ft := f.Type()
-			v := inj.Get(ft)
-			if !v.IsValid() {
-				return fmt.Errorf("Value not found for type %v", ft)
-			}
+	fields := inj.injectable(t)
+	for k := range fields {
+		structField := &fields[k]
+		f, ok := settable(v, structField.Index)
+		if !ok {
+			continue
+		}
+		ft := f.Type()
+		start := inj.started()
+		v, err := inj.fieldValue(t, structField, c)
+		inj.remember(t.String, ft, start, err)
+		if err != nil {
+			return &InjectionError{t.String(), structField.Name, ft, err}
+		}
 
-			f.Set(v)
+		f.Set(v)
+		c.advance(t, structField.Name)
+		if c.applied != nil {
+			c.applied(structField.StructField)
 		}
+	}
+
+	return nil
+}
 
+// fieldValue resolves the tagged field f of the struct type t as part of c.
+func (inj *injector) fieldValue(t reflect.Type, f *injectField, c *call) (reflect.Value, error) {
+	prev := c.consumer
+	defer func() { c.consumer = prev }()
+	c.consumer = consumer{owner: t, field: f.Name}
+	if err := inj.enforce(c); err != nil {
+		return reflect.Value{}, err
+	}
+	return inj.field(f, c, func(t reflect.Type) (reflect.Value, error) {
+		return inj.lookup(t, c)
+	})
+}
+
+// Populate sets the value each of ptrs points to from the Type map, which is
+// convenient to pull a few top-level components out of a wired injector.
+// Returns an InjectionError if an argument is not a non-nil pointer or if
+// the type it points to cannot be resolved. Nothing is assigned when an
+// error occurs.
+func (inj *injector) Populate(ptrs ...interface{}) error {
+	c := newCall(inj)
+	vals := make([]reflect.Value, len(ptrs))
+	for i, ptr := range ptrs {
+		v := reflect.ValueOf(ptr)
+		if v.Kind() != reflect.Ptr || v.IsNil() {
+			return &InjectionError{"Populate", fmt.Sprintf("#%d", i), reflect.TypeOf(ptr), errors.New("Populate requires non-nil pointers")}
+		}
+
+		start := inj.started()
+		val, err := inj.lookup(v.Type().Elem(), c)
+		inj.remember(func() string { return "Populate" }, v.Type().Elem(), start, err)
+		if err != nil {
+			return &InjectionError{"Populate", fmt.Sprintf("#%d", i), v.Type().Elem(), err}
+		}
+		vals[i] = val
+	}
+
+	for i, ptr := range ptrs {
+		reflect.ValueOf(ptr).Elem().Set(vals[i])
 	}
 
 	return nil
@@ -139,30 +493,248 @@ func (inj *injector) Apply(val interface{}) error {
 // Maps the concrete value of val to its dynamic type using reflect.TypeOf,
 // It returns the TypeMapper registered in.
 func (i *injector) Map(val interface{}) TypeMapper {
-	i.values[reflect.TypeOf(val)] = reflect.ValueOf(val)
-	return i
+	return i.MapWith(val)
+}
+
+// MapWith works like Map and registers the binding with opts.
+func (i *injector) MapWith(val interface{}, opts ...BindOption) TypeMapper {
+	return i.SetWith(reflect.TypeOf(val), reflect.ValueOf(val), opts...)
 }
 
 func (i *injector) MapTo(val interface{}, ifacePtr interface{}) TypeMapper {
-	i.values[InterfaceOf(ifacePtr)] = reflect.ValueOf(val)
-	return i
+	return i.MapToWith(val, ifacePtr)
+}
+
+// MapToWith works like MapTo and registers the binding with opts.
+func (i *injector) MapToWith(val interface{}, ifacePtr interface{}, opts ...BindOption) TypeMapper {
+	return i.SetWith(InterfaceOf(ifacePtr), reflect.ValueOf(val), opts...)
 }
 
 // Maps the given reflect.Type to the given reflect.Value and returns
 // the Typemapper the mapping has been registered in.
 func (i *injector) Set(typ reflect.Type, val reflect.Value) TypeMapper {
-	i.values[typ] = val
+	return i.SetWith(typ, val)
+}
+
+// SetWith works like Set and registers the binding with opts.
+func (i *injector) SetWith(typ reflect.Type, val reflect.Value, opts ...BindOption) TypeMapper {
+	i.bind(typ, newBinding(val, nil, opts))
 	return i
 }
 
 func (i *injector) Get(t reflect.Type) reflect.Value {
-	val := i.values[t]
-	if !val.IsValid() && i.parent != nil {
-		val = i.parent.Get(t)
+	if val, ok := i.getMapped(t); ok {
+		return val
 	}
+	val, _ := i.resolve(t)
 	return val
 }
 
-func (i *injector) SetParent(parent Injector) {
-	i.parent = parent
+// getMapped returns the value mapped to t in the injector itself, which
+// Get hands out without starting a call: nothing is built and no parent is
+// asked for it.
+func (i *injector) getMapped(t reflect.Type) (reflect.Value, bool) {
+	if t == targetType || i.delegation == ParentFirst {
+		return reflect.Value{}, false
+	}
+	e, ok := i.snapshot().entry(t)
+	if !ok || e.provider != nil || e.ref != nil {
+		return reflect.Value{}, false
+	}
+	i.resolved(t)
+	val := e.mapped()
+	if e.alias != nil {
+		val = val.Convert(t)
+	}
+	return i.handOut(t, e, val), true
+}
+
+// resolve resolves t for a request made to the injector itself.
+func (i *injector) resolve(t reflect.Type) (reflect.Value, error) {
+	return i.lookup(t, newCall(i))
+}
+
+// lookup looks t up in the Type map and among the providers, then in the
+// parent injector, or in the reverse order when the delegation policy is
+// ParentFirst. Values built by providers are stored in the Type map so that
+// every later request yields the same value. Unless it is mapped, Injector
+// resolves to the injector c started from. In test mode a fake is returned
+// for types that cannot be resolved otherwise.
+// A dry call resolves the zero value of the types built by providers and
+// records where every dependency was found when c.found is set, so that
+// Validate and ExplainInvoke follow the very path of a resolution.
+func (i *injector) lookup(t reflect.Type, c *call) (reflect.Value, error) {
+	if t == targetType {
+		c.step(TraceBuiltin, i, t, "consumer of the call")
+		c.find(FromTarget, i)
+		return reflect.ValueOf(c.target()), nil
+	}
+	if t == contextType && c.ctx != nil {
+		c.step(TraceBuiltin, i, t, "context of the call")
+		c.find(FromCallContext, i)
+		return reflect.ValueOf(&c.ctx).Elem(), nil
+	}
+	if val, ok := c.values[t]; ok && i == c.origin {
+		c.step(TraceBuiltin, i, t, "value of the call context")
+		c.find(FromCallContext, i)
+		return val, nil
+	}
+	lookups := [2]func(reflect.Type, *call) (reflect.Value, error){i.lookupLocal, i.lookupParent}
+	if i.delegation == ParentFirst {
+		lookups[0], lookups[1] = lookups[1], lookups[0]
+	}
+	for _, lookup := range lookups {
+		if val, err := lookup(t, c); !missing(err) {
+			return val, err
+		}
+	}
+	if c.extras != nil && i == c.origin {
+		if val, err := i.lookupExtra(t, c); !missing(err) {
+			return val, err
+		}
+	}
+	if t == injectorType {
+		c.step(TraceBuiltin, i, t, "requesting injector")
+		if c.found != nil {
+			*c.found = found{FromInjector, 0, c.origin.Scope()}
+		}
+		var origin Injector = c.origin
+		return reflect.ValueOf(&origin).Elem(), nil
+	}
+	if t == contextType {
+		c.step(TraceBuiltin, i, t, "background context")
+		c.find(FromCallContext, i)
+		ctx := context.Background()
+		return reflect.ValueOf(&ctx).Elem(), nil
+	}
+	// the adapters resolve t from another bound type, see
+	// bidirectional, sameSignature and implementation; those setting a
+	// source report it instead of the source of the other type
+	adapters := [...]struct {
+		adapt  func(reflect.Type) (reflect.Type, bool)
+		source Source
+	}{{bidirectional, ""}, {i.sameSignature, ""}, {i.implementation, FromImplementation}}
+	for _, a := range adapters {
+		if from, ok := a.adapt(t); ok {
+			c.step(TraceAdapt, i, t, "from "+from.String())
+			if val, err := i.lookup(from, c); !missing(err) {
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				if a.source != "" && c.found != nil {
+					c.found.source = a.source
+				}
+				return val.Convert(t), nil
+			}
+		}
+	}
+	// parents leave the options of the injector c started from to it
+	if collectable(t) && i == c.origin {
+		if val, err := i.collect(t, c); !missing(err) {
+			c.find(FromGroup, i)
+			return val, err
+		}
+	}
+	if i.conversions {
+		if val, err := i.convert(t, c); !missing(err) {
+			c.step(TraceAdapt, i, t, "conversion")
+			return val, err
+		}
+	}
+	if val, ok := i.fake(t, c); ok {
+		c.step(TraceBuiltin, i, t, "fake")
+		c.find(FromFake, i)
+		return val, nil
+	}
+	c.step(TraceMiss, i, t, "")
+	return reflect.Value{}, i.notFound(t)
+}
+
+// lookupLocal resolves t from the Type map and the providers of the epoch
+// of c only.
+func (i *injector) lookupLocal(t reflect.Type, c *call) (reflect.Value, error) {
+	e, ok := c.bindings(i).entry(t)
+	if !ok {
+		return reflect.Value{}, ErrNotFound
+	}
+
+	val := e.value
+	switch {
+	case c.marks:
+		i.markUsed(t)
+	case !c.dry:
+		i.resolved(t)
+		val = e.mapped()
+		if e.serial != nil {
+			c.hold(e)
+		}
+	}
+	if e.ref != nil {
+		c.step(TraceLocal, i, t, "reference")
+		var err error
+		if val, err = i.lookupRef(t, e, c); err != nil {
+			return reflect.Value{}, err
+		}
+	} else if e.provider == nil {
+		c.step(TraceLocal, i, t, "binding")
+		if e.supply != nil && c.dry {
+			// the supplier is not called by a dry call
+			val = reflect.Zero(t)
+		}
+		c.find(FromBinding, i)
+	} else {
+		c.step(TraceLocal, i, t, "provider")
+		from := t
+		if e.alias != nil {
+			from = e.alias
+		}
+		var err error
+		if val, err = i.provide(from, e, c); err != nil {
+			return reflect.Value{}, err
+		}
+		c.find(FromProvider, i)
+	}
+	if e.alias != nil {
+		val = val.Convert(t)
+	}
+	if c.dry {
+		return val, nil
+	}
+	val = i.handOut(t, e, val)
+	i.watch(t, e, val, c)
+	return val, nil
+}
+
+// lookupParent resolves t from the first parent able to resolve it among
+// those the delegation policy and the parent filters let the injector ask.
+func (i *injector) lookupParent(t reflect.Type, c *call) (reflect.Value, error) {
+	if !i.delegates(t) {
+		return reflect.Value{}, ErrNotFound
+	}
+	for _, p := range c.bindings(i).parents {
+		if !p.allows(t) {
+			continue
+		}
+		c.step(TraceParent, i, t, "to scope "+string(p.inj.Scope()))
+		c.hops++
+		val, err := delegate(p.inj, t, c)
+		c.hops--
+		if !missing(err) {
+			return val, err
+		}
+	}
+	return reflect.Value{}, ErrNotFound
+}
+
+// delegate resolves t from inj, a parent or another injector consulted by
+// c. Injectors of other implementations are asked with Get.
+func delegate(inj Injector, t reflect.Type, c *call) (reflect.Value, error) {
+	if r, ok := inj.(resolver); ok {
+		return r.lookup(t, c)
+	}
+	if val := inj.Get(t); val.IsValid() {
+		c.find(FromBinding, inj)
+		return val, nil
+	}
+	return reflect.Value{}, ErrNotFound
 }
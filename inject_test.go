@@ -140,3 +140,19 @@ func Test_InjectorSetParent(t *testing.T) {
 
 	expect(t, injector2.Get(inject.InterfaceOf((*SpecialString)(nil))).IsValid(), true)
 }
+
+func Test_InjectorPopulate(t *testing.T) {
+	injector := inject.New()
+	injector.Map("a dep").MapTo("another dep", (*SpecialString)(nil))
+
+	var dep string
+	var special SpecialString
+	err := injector.Populate(&dep, &special)
+	expect(t, err, nil)
+	expect(t, dep, "a dep")
+	expect(t, special, "another dep")
+
+	var missing int
+	expect(t, injector.Populate(&missing) == nil, false)
+	expect(t, injector.Populate(dep) == nil, false)
+}
@@ -0,0 +1,69 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// KeyedBy returns a BindOption memoizing the values of the provider of the
+// binding by the values the resolution resolves for types, such as the
+// tenant or the locale of a request, instead of once. The key values are
+// resolved from the injector the resolution started from, typically a
+// request scope, and so is the provider when the value of a key is first
+// built: a provider bound in the application injector then serves one
+// instance per tenant to every request of that tenant. The values are
+// owned, and closed by Dispose, by the injector of the binding.
+// KeyedBy has no effect on mapped values and transient providers.
+// It panics if one of types is not comparable.
+func KeyedBy(types ...reflect.Type) BindOption {
+	for _, t := range types {
+		if !t.Comparable() {
+			panic(fmt.Sprintf("Called inject.KeyedBy with type %v that is not comparable", t))
+		}
+	}
+	return func(e *binding) {
+		e.keys = types
+	}
+}
+
+// provideKeyed returns the value of the memoized provider of e for the key
+// values of c, building it from the injector c started from for a new
+// key. The provider lock is held.
+func (i *injector) provideKeyed(t reflect.Type, e *binding, c *call) (reflect.Value, error) {
+	in := make([]reflect.Value, len(e.keys))
+	for n, kt := range e.keys {
+		val, err := c.origin.lookup(kt, c)
+		if err != nil {
+			return reflect.Value{}, &ProviderError{t, fmt.Errorf("Cannot resolve cache key %v: %w", kt, err)}
+		}
+		in[n] = val
+	}
+	key, ok := cacheKey(in)
+	if !ok {
+		return reflect.Value{}, &ProviderError{t, fmt.Errorf("Cache keys of types %v are not all comparable", e.keys)}
+	}
+
+	p := e.provider
+	if val, ok := p.keyed[key]; ok {
+		return val, nil
+	}
+	if err := c.done(t); err != nil {
+		return reflect.Value{}, err
+	}
+	val, fresh, err := c.origin.build(t, e, c)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if fresh {
+		if err := created(t, e, val); err != nil {
+			return reflect.Value{}, err
+		}
+		i.track(val.Interface())
+		i.destroyed(e, val)
+	}
+	if p.keyed == nil {
+		p.keyed = make(map[interface{}]reflect.Value)
+	}
+	p.keyed[key] = val
+	return val, nil
+}
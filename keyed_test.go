@@ -0,0 +1,59 @@
+package inject_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type tenantID string
+
+type tenantClient struct {
+	tenant tenantID
+	closed bool
+}
+
+func (c *tenantClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func Test_InjectorKeyedBy(t *testing.T) {
+	app := inject.New()
+	calls := 0
+	app.Provide(func(id tenantID) *tenantClient {
+		calls++
+		return &tenantClient{tenant: id}
+	}, inject.KeyedBy(reflect.TypeOf(tenantID(""))))
+
+	client := func(tenant tenantID) *tenantClient {
+		req := app.Enter(inject.Request)
+		defer req.Dispose()
+		req.Map(tenant)
+		var c *tenantClient
+		expect(t, req.Populate(&c), nil)
+		return c
+	}
+	acme, globex := client("acme"), client("globex")
+	expect(t, acme.tenant, tenantID("acme"))
+	expect(t, globex.tenant, tenantID("globex"))
+	expect(t, client("acme"), acme)
+	expect(t, calls, 2)
+	expect(t, acme.closed, false)
+
+	// the key cannot be resolved from the application itself
+	_, err := app.Invoke(func(*tenantClient) {})
+	expect(t, err != nil, true)
+
+	expect(t, app.Dispose(), nil)
+	expect(t, acme.closed, true)
+	expect(t, globex.closed, true)
+}
+
+func Test_KeyedByPanics(t *testing.T) {
+	defer func() {
+		expect(t, recover(), "Called inject.KeyedBy with type []string that is not comparable")
+	}()
+	inject.KeyedBy(reflect.TypeOf([]string(nil)))
+}
@@ -0,0 +1,43 @@
+package inject_test
+
+import (
+	"bytes"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+// goroutineLabels returns the goroutine profile, which lists the labels of
+// every goroutine.
+func goroutineLabels() string {
+	var buf bytes.Buffer
+	pprof.Lookup("goroutine").WriteTo(&buf, 1)
+	return buf.String()
+}
+
+func handle(*Config) string {
+	return goroutineLabels()
+}
+
+func Test_InjectorProfileLabels(t *testing.T) {
+	injector := inject.New(inject.ProfileLabels())
+	var provided string
+	injector.Provide(func() *Config {
+		provided = goroutineLabels()
+		return &Config{}
+	})
+
+	out, err := injector.Invoke(handle)
+	expect(t, err, nil)
+	expect(t, strings.Contains(out[0].String(), `"inject.func":"inject_test.handle"`), true)
+	expect(t, strings.Contains(provided, `"inject.func":"inject_test.Test_InjectorProfileLabels.func1"`), true)
+	expect(t, strings.Contains(goroutineLabels(), `"inject.func"`), false)
+
+	injector = inject.New()
+	injector.Map(&Config{})
+	out, err = injector.Invoke(handle)
+	expect(t, err, nil)
+	expect(t, strings.Contains(out[0].String(), `"inject.func"`), false)
+}
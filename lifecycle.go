@@ -0,0 +1,40 @@
+package inject
+
+import (
+	"errors"
+	"io"
+)
+
+// OnDispose registers fn to be called when the injector is disposed.
+func (i *injector) OnDispose(fn func() error) {
+	i.mu.Lock()
+	i.disposers = append(i.disposers, fn)
+	i.mu.Unlock()
+}
+
+// Dispose releases the resources owned by the injector. Functions
+// registered with OnDispose and values built by the providers of the
+// injector that implement io.Closer are released in the reverse order of
+// their registration or construction. Dispose does not affect the parent.
+// Returns an error joining the errors of every failed release.
+func (i *injector) Dispose() error {
+	i.mu.Lock()
+	disposers := i.disposers
+	i.disposers = nil
+	i.mu.Unlock()
+
+	var errs []error
+	for n := len(disposers) - 1; n >= 0; n-- {
+		if err := disposers[n](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// track registers the release of a value built by a provider.
+func (i *injector) track(val interface{}) {
+	if c, ok := val.(io.Closer); ok {
+		i.OnDispose(c.Close)
+	}
+}
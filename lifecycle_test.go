@@ -0,0 +1,38 @@
+package inject_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type closer struct {
+	name   string
+	closed *[]string
+}
+
+func (c *closer) Close() error {
+	*c.closed = append(*c.closed, c.name)
+	return nil
+}
+
+func Test_InjectorDispose(t *testing.T) {
+	var closed []string
+	injector := inject.New()
+	injector.Map(&closer{"mapped", &closed})
+	injector.Provide(func() *closer { return &closer{"provided", &closed} })
+	injector.OnDispose(func() error {
+		closed = append(closed, "hook")
+		return errors.New("boom")
+	})
+
+	_, err := injector.Invoke(func(*closer) {})
+	expect(t, err, nil)
+
+	err = injector.Dispose()
+	refute(t, err, nil)
+	expect(t, len(closed), 1)
+	expect(t, closed[0], "hook")
+	expect(t, injector.Dispose(), nil)
+}
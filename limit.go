@@ -0,0 +1,30 @@
+package inject
+
+import "reflect"
+
+// MaxConcurrentProviders returns an Option letting at most n providers of
+// the injector run at once, however many goroutines resolve their types,
+// which protects databases and other backends from connection storms
+// during a parallel warm-up. Only the provider functions themselves are
+// limited: their arguments are resolved before a slot is taken. Providers
+// of parents and transient providers resolved from other injectors are
+// limited by the option of the injector that invokes them.
+// It panics if n is not positive.
+func MaxConcurrentProviders(n int) Option {
+	if n <= 0 {
+		panic("Called inject.MaxConcurrentProviders with a limit that is not positive")
+	}
+	return func(i *injector) {
+		i.slots = make(chan struct{}, n)
+	}
+}
+
+// limit calls fn with in as part of c once a construction slot is
+// available.
+func (i *injector) limit(fn reflect.Value, in []reflect.Value, c *call) []reflect.Value {
+	if i.slots != nil {
+		i.slots <- struct{}{}
+		defer func() { <-i.slots }()
+	}
+	return i.run(fn, in, c)
+}
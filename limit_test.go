@@ -0,0 +1,47 @@
+package inject_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorMaxConcurrentProviders(t *testing.T) {
+	var running, peak int32
+	slow := func() {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	}
+
+	injector := inject.New(inject.MaxConcurrentProviders(2))
+	injector.Provide(func() int8 { slow(); return 1 })
+	injector.Provide(func() int16 { slow(); return 2 })
+	injector.Provide(func() int32 { slow(); return 3 })
+	injector.Provide(func() int64 { slow(); return 4 })
+	// depends on the other providers, which must not deadlock
+	injector.Provide(func(int8, int16) uint { return 5 })
+
+	var wg sync.WaitGroup
+	for _, f := range []interface{}{
+		func(int8) {}, func(int16) {}, func(int32) {}, func(int64) {}, func(uint) {},
+	} {
+		wg.Add(1)
+		go func(f interface{}) {
+			defer wg.Done()
+			_, err := injector.Invoke(f)
+			expect(t, err, nil)
+		}(f)
+	}
+	wg.Wait()
+	expect(t, atomic.LoadInt32(&peak) <= 2, true)
+}
@@ -0,0 +1,21 @@
+package inject
+
+// Logger receives the warnings of an injector. *log.Logger implements it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// WithLogger returns an Option sending the warnings of the injector to l.
+// Warnings are discarded by default.
+func WithLogger(l Logger) Option {
+	return func(i *injector) {
+		i.logger = l
+	}
+}
+
+// warnf sends a warning to the logger of the injector, if any.
+func (i *injector) warnf(format string, v ...interface{}) {
+	if i.logger != nil {
+		i.logger.Printf("inject: "+format, v...)
+	}
+}
@@ -0,0 +1,109 @@
+package inject
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+)
+
+// TB is the subset of testing.TB used by AssertResolvable.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Requirement tells whether a dependency of an entry point can currently be
+// resolved.
+type Requirement struct {
+	Type     reflect.Type
+	Resolved bool
+	Err      error
+}
+
+// EntryPoint lists the requirements of a function.
+type EntryPoint struct {
+	Name         string
+	Requirements []Requirement
+}
+
+// Matrix is the resolvability matrix of a set of entry points.
+type Matrix []EntryPoint
+
+// ResolvabilityMatrix checks every argument of each of fns against inj,
+// without calling any function or provider.
+// It panics if one of fns is not a function.
+func ResolvabilityMatrix(inj Injector, fns ...interface{}) Matrix {
+	m := make(Matrix, 0, len(fns))
+	for _, fn := range fns {
+		v := reflect.ValueOf(fn)
+		if v.Kind() != reflect.Func {
+			panic("Called inject.ResolvabilityMatrix with a value that is not a function")
+		}
+
+		e := EntryPoint{Name: funcName(v)}
+		for n := 0; n < v.Type().NumIn(); n++ {
+			t := v.Type().In(n)
+			err := inj.Validate(reflect.Zero(reflect.FuncOf([]reflect.Type{t}, nil, false)).Interface())
+			e.Requirements = append(e.Requirements, Requirement{Type: t, Resolved: err == nil, Err: err})
+		}
+		m = append(m, e)
+	}
+	return m
+}
+
+// Complete reports whether every requirement of the matrix is resolved.
+func (m Matrix) Complete() bool {
+	for _, e := range m {
+		for _, r := range e.Requirements {
+			if !r.Resolved {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String formats the matrix as a table with one line per requirement.
+func (m Matrix) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ENTRY POINT\tDEPENDENCY\tRESOLVED")
+	for _, e := range m {
+		name := e.Name
+		if len(e.Requirements) == 0 {
+			fmt.Fprintf(w, "%s\t-\t-\n", name)
+		}
+		for _, r := range e.Requirements {
+			resolved := "yes"
+			if !r.Resolved {
+				resolved = "NO"
+			}
+			fmt.Fprintf(w, "%s\t%v\t%s\n", name, r.Type, resolved)
+			name = ""
+		}
+	}
+	w.Flush()
+	return buf.String()
+}
+
+// AssertResolvable fails t with the resolvability matrix of fns when one of
+// their arguments cannot be resolved from inj.
+func AssertResolvable(t TB, inj Injector, fns ...interface{}) {
+	t.Helper()
+	if m := ResolvabilityMatrix(inj, fns...); !m.Complete() {
+		t.Errorf("Unresolved dependencies:\n%s", m)
+	}
+}
+
+// funcName returns the package qualified name of the function v, or its
+// type if the name is not known.
+func funcName(v reflect.Value) string {
+	if f := runtime.FuncForPC(v.Pointer()); f != nil {
+		name := f.Name()
+		return name[strings.LastIndex(name, "/")+1:]
+	}
+	return v.Type().String()
+}
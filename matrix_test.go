@@ -0,0 +1,45 @@
+package inject_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func handleUsers(c *Config, s string) {}
+
+func Test_ResolvabilityMatrix(t *testing.T) {
+	injector := inject.New()
+	injector.Map("a dep")
+
+	m := inject.ResolvabilityMatrix(injector, handleUsers, func() {})
+	expect(t, len(m), 2)
+	expect(t, m.Complete(), false)
+	expect(t, m[0].Requirements[0].Resolved, false)
+	expect(t, m[0].Requirements[1].Resolved, true)
+	lines := strings.Split(m.String(), "\n")
+	expect(t, strings.Fields(lines[1])[0], "inject_test.handleUsers")
+	expect(t, strings.Fields(lines[1])[2], "NO")
+	expect(t, strings.Fields(lines[2])[1], "yes")
+
+	r := &recorder{}
+	inject.AssertResolvable(r, injector, handleUsers)
+	expect(t, len(r.errors), 1)
+
+	injector.Map(&Config{})
+	r = &recorder{}
+	inject.AssertResolvable(r, injector, handleUsers)
+	expect(t, len(r.errors), 0)
+}
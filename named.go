@@ -0,0 +1,120 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// namedKey identifies a binding registered with the Named option.
+type namedKey struct {
+	typ  reflect.Type
+	name string
+}
+
+// Named returns a BindOption registering the binding under name, next to
+// the unnamed binding of its type rather than replacing it. Named bindings
+// are injected into fields tagged `inject:"name=<name>"` and gathered by
+// map[string]T fields tagged `inject:"named"`, e.g. a registry of codecs
+// keyed by their names.
+func Named(name string) BindOption {
+	return func(e *binding) {
+		e.name = name
+	}
+}
+
+// lookupNamed resolves the binding of t named name from the injector and
+// its parents.
+func (i *injector) lookupNamed(t reflect.Type, name string, c *call) (reflect.Value, error) {
+	if e, ok := c.bindings(i).named[namedKey{t, name}]; ok {
+		val := e.value
+		if !c.dry {
+			val = e.mapped()
+		} else if e.supply != nil {
+			val = reflect.Zero(t)
+		}
+		if e.provider != nil {
+			var err error
+			if val, err = i.provide(t, e, c); err != nil {
+				return reflect.Value{}, err
+			}
+			c.find(FromProvider, i)
+		} else {
+			c.find(FromBinding, i)
+		}
+		if c.dry {
+			return val, nil
+		}
+		val = i.handOut(t, e, val)
+		i.watch(t, e, val, c)
+		return val, nil
+	}
+	for _, p := range i.namedParents(t) {
+		c.hops++
+		val, err := p.lookupNamed(t, name, c)
+		c.hops--
+		if !missing(err) {
+			return val, err
+		}
+	}
+	return reflect.Value{}, &NotFoundError{Type: t, Hints: []string{fmt.Sprintf("no binding named %q", name)}}
+}
+
+// namedParents returns the parents asked for the named bindings of t.
+func (i *injector) namedParents(t reflect.Type) []*injector {
+	if !i.delegates(t) {
+		return nil
+	}
+	var parents []*injector
+	for _, p := range i.snapshot().parents {
+		if pi, ok := p.inj.(*injector); ok && p.allows(t) {
+			parents = append(parents, pi)
+		}
+	}
+	return parents
+}
+
+// names returns the named bindings of the injector and its parents whose
+// type is assignable to t, by name. A name bound at several levels maps to
+// the binding closest to the injector.
+func (i *injector) names(t reflect.Type, c *call) map[string]reflect.Type {
+	names := make(map[string]reflect.Type)
+	for _, p := range i.namedParents(t) {
+		for name, typ := range p.names(t, c) {
+			if _, ok := names[name]; !ok {
+				names[name] = typ
+			}
+		}
+	}
+	var keys []namedKey
+	for k := range c.bindings(i).named {
+		if k.typ.AssignableTo(t) {
+			keys = append(keys, k)
+		}
+	}
+	// the exact type wins over other assignable types bound under a name
+	sort.Slice(keys, func(a, b int) bool {
+		if ea, eb := keys[a].typ == t, keys[b].typ == t; ea != eb {
+			return eb
+		}
+		return keys[a].typ.String() < keys[b].typ.String()
+	})
+	for _, k := range keys {
+		names[k.name] = k.typ
+	}
+	return names
+}
+
+// namedMap resolves a map of type t holding every named binding assignable
+// to the element type of t, keyed by name.
+func (i *injector) namedMap(t reflect.Type, c *call) (reflect.Value, error) {
+	m := reflect.MakeMap(t)
+	for name, typ := range i.names(t.Elem(), c) {
+		val, err := i.lookupNamed(typ, name, c)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		m.SetMapIndex(reflect.ValueOf(name).Convert(t.Key()), val)
+	}
+	return m, nil
+}
@@ -0,0 +1,46 @@
+package inject_test
+
+import (
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type Codec interface {
+	Encode(string) string
+}
+
+type upper struct{}
+
+func (upper) Encode(s string) string { return "U:" + s }
+
+type lower struct{}
+
+func (lower) Encode(s string) string { return "L:" + s }
+
+func Test_InjectorNamedBindings(t *testing.T) {
+	parent := inject.New()
+	parent.MapToWith(lower{}, (*Codec)(nil), inject.Named("lower"))
+	parent.MapToWith(lower{}, (*Codec)(nil), inject.Named("json"))
+	injector := inject.New()
+	injector.SetParent(parent)
+	injector.MapToWith(upper{}, (*Codec)(nil), inject.Named("json"))
+	injector.MapTo(upper{}, (*Codec)(nil))
+
+	s := struct {
+		Codecs  map[string]Codec `inject:"named"`
+		JSON    Codec            `inject:"name=json"`
+		Default Codec            `inject`
+	}{}
+	expect(t, injector.Apply(&s), nil)
+	expect(t, len(s.Codecs), 2)
+	expect(t, s.Codecs["json"].Encode("x"), "U:x")
+	expect(t, s.Codecs["lower"].Encode("x"), "L:x")
+	expect(t, s.JSON.Encode("x"), "U:x")
+	expect(t, s.Default.Encode("x"), "U:x")
+
+	missing := struct {
+		C Codec `inject:"name=xml"`
+	}{}
+	refute(t, injector.Apply(&missing), nil)
+}
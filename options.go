@@ -0,0 +1,4 @@
+package inject
+
+// Option configures an injector created by New.
+type Option func(*injector)
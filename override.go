@@ -0,0 +1,45 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Override forces the value of an argument of a function invoked by
+// InvokeOverriding.
+type Override struct {
+	// Index is the position of the argument, starting at 0.
+	Index int
+	// Value is passed for the argument. A nil Value passes the zero value
+	// of the argument type.
+	Value interface{}
+}
+
+// InvokeOverriding calls f like Invoke, except that the arguments at the
+// positions of overrides receive their values instead of being resolved.
+// It is needed when f takes two arguments of the same type and only one of
+// them is mapped, e.g. InvokeOverriding(copy, Override{Index: 1, Value: dst}).
+// Returns an error if an override is out of the range of the arguments of
+// f or holds a value that is not assignable to its argument.
+// It panics if f is not a function.
+func (inj *injector) InvokeOverriding(f interface{}, overrides ...Override) ([]reflect.Value, error) {
+	t := reflect.TypeOf(f)
+	name := funcName(reflect.ValueOf(f))
+	fixed := make(map[int]reflect.Value, len(overrides))
+	for _, o := range overrides {
+		if o.Index < 0 || o.Index >= t.NumIn() {
+			return nil, fmt.Errorf("Cannot override argument #%d of %s, which takes %d arguments", o.Index, name, t.NumIn())
+		}
+		argType := t.In(o.Index)
+		if o.Value == nil {
+			fixed[o.Index] = reflect.Zero(argType)
+			continue
+		}
+		val := reflect.ValueOf(o.Value)
+		if !val.Type().AssignableTo(argType) {
+			return nil, fmt.Errorf("Cannot override argument #%d %v of %s with a value of type %v", o.Index, argType, name, val.Type())
+		}
+		fixed[o.Index] = val
+	}
+	return inj.invoke(f, newCall(inj), fixed)
+}
@@ -0,0 +1,30 @@
+package inject_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorInvokeOverriding(t *testing.T) {
+	injector := inject.New()
+	injector.Map("src").Map(2)
+
+	concat := func(a, b string, n int) string {
+		return strings.Repeat(a+b, n)
+	}
+	out, err := injector.InvokeOverriding(concat, inject.Override{Index: 1, Value: "dst"})
+	expect(t, err, nil)
+	expect(t, out[0].String(), "srcdstsrcdst")
+
+	out, err = injector.InvokeOverriding(concat, inject.Override{Index: 0}, inject.Override{Index: 2, Value: 1})
+	expect(t, err, nil)
+	expect(t, out[0].String(), "src")
+
+	_, err = injector.InvokeOverriding(concat, inject.Override{Index: 3, Value: 1})
+	refute(t, err, nil)
+	_, err = injector.InvokeOverriding(concat, inject.Override{Index: 2, Value: "x"})
+	refute(t, err, nil)
+	expect(t, strings.Contains(err.Error(), "with a value of type string"), true)
+}
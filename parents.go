@@ -0,0 +1,48 @@
+package inject
+
+import "reflect"
+
+// parentLink is a parent of an injector along with the filter restricting
+// the types it is asked for.
+type parentLink struct {
+	inj    Injector
+	filter func(reflect.Type) bool
+}
+
+// allows reports whether the parent may be asked for t.
+func (p parentLink) allows(t reflect.Type) bool {
+	return p.filter == nil || p.filter(t)
+}
+
+// SetParent sets the parent of the injector, replacing every parent added
+// with AddParent.
+func (i *injector) SetParent(parent Injector) {
+	i.update(func(b *bindings) {
+		b.parents = []parentLink{{inj: parent}}
+	})
+}
+
+// AddParent appends parent to the parents of the injector. Parents are
+// asked in the order they were added for the types the injector cannot
+// resolve itself. When filter is not nil, parent is only asked for the
+// types filter accepts, so that a plugin injector can, for example, only
+// inherit the logging and configuration types of its host.
+func (i *injector) AddParent(parent Injector, filter func(reflect.Type) bool) {
+	i.update(func(b *bindings) {
+		// copied since the slice is shared with older snapshots
+		parents := make([]parentLink, len(b.parents), len(b.parents)+1)
+		copy(parents, b.parents)
+		b.parents = append(parents, parentLink{parent, filter})
+	})
+}
+
+// Types returns a filter for AddParent accepting only the given types.
+func Types(types ...reflect.Type) func(reflect.Type) bool {
+	set := make(map[reflect.Type]bool, len(types))
+	for _, t := range types {
+		set[t] = true
+	}
+	return func(t reflect.Type) bool {
+		return set[t]
+	}
+}
@@ -0,0 +1,50 @@
+package inject_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorAddParent(t *testing.T) {
+	host := inject.New()
+	host.Map("host dep").Map(42).Map(&Config{Name: "host"})
+	platform := inject.New()
+	platform.Map(1.5).Map(7)
+
+	plugin := inject.New()
+	plugin.AddParent(host, inject.Types(reflect.TypeOf(""), reflect.TypeOf(&Config{})))
+	plugin.AddParent(platform, nil)
+
+	expect(t, plugin.Get(reflect.TypeOf("")).String(), "host dep")
+	expect(t, plugin.Get(reflect.TypeOf(0)).Interface(), 7)
+	expect(t, plugin.Get(reflect.TypeOf(1.5)).Interface(), 1.5)
+	expect(t, plugin.Validate(func(*Config, int) {}), nil)
+
+	plugin.SetParent(platform)
+	expect(t, plugin.Get(reflect.TypeOf("")).IsValid(), false)
+}
+
+func Test_InjectorSetParentConcurrently(t *testing.T) {
+	parent := inject.New()
+	parent.Map("parent dep")
+	child := inject.New()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for n := 0; n < 100; n++ {
+			child.SetParent(parent)
+			child.AddParent(inject.New(), nil)
+			child.SetScope(inject.Request)
+		}
+	}()
+	for n := 0; n < 100; n++ {
+		child.Get(reflect.TypeOf(""))
+		child.Scope()
+	}
+	<-done
+	expect(t, child.Get(reflect.TypeOf("")).String(), "parent dep")
+	expect(t, child.Scope(), inject.Request)
+}
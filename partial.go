@@ -0,0 +1,62 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// InvokePartial resolves every argument of f the injector can resolve and
+// returns a function taking only the others, in order, that calls f with
+// the resolved arguments and the ones it receives. The variadic argument
+// of f, if any, is never resolved and stays the variadic argument of the
+// returned function. It adapts a third-party callback whose signature
+// mixes dependencies with the arguments supplied by its caller, e.g. a
+// func(*sql.DB, http.ResponseWriter, *http.Request) becomes a
+// func(http.ResponseWriter, *http.Request) once *sql.DB is mapped. The
+// arguments are resolved once, when InvokePartial is called.
+// Returns an error if resolving an argument fails for another reason than
+// its type not being bound.
+// It panics if f is not a function.
+func (inj *injector) InvokePartial(f interface{}) (interface{}, error) {
+	fv := reflect.ValueOf(f)
+	t := fv.Type()
+	c := newCall(inj)
+
+	resolved := make([]reflect.Value, t.NumIn())
+	var ins []reflect.Type
+	for n := 0; n < t.NumIn(); n++ {
+		if t.IsVariadic() && n == t.NumIn()-1 {
+			ins = append(ins, t.In(n))
+			break
+		}
+		val, err := inj.lookup(t.In(n), c)
+		if missing(err) {
+			ins = append(ins, t.In(n))
+			continue
+		}
+		if err != nil {
+			return nil, &InjectionError{funcName(fv), fmt.Sprintf("#%d", n), t.In(n), err}
+		}
+		resolved[n] = val
+	}
+	outs := make([]reflect.Type, t.NumOut())
+	for n := range outs {
+		outs[n] = t.Out(n)
+	}
+
+	ft := reflect.FuncOf(ins, outs, t.IsVariadic())
+	return reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+		in := make([]reflect.Value, len(resolved))
+		for n := range in {
+			if resolved[n].IsValid() {
+				in[n] = resolved[n]
+			} else {
+				in[n], args = args[0], args[1:]
+			}
+		}
+		if t.IsVariadic() {
+			return fv.CallSlice(in)
+		}
+		return fv.Call(in)
+	}).Interface(), nil
+}
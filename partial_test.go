@@ -0,0 +1,33 @@
+package inject_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorInvokePartial(t *testing.T) {
+	injector := inject.New()
+	injector.Map("db").Map(&Config{Name: "cfg"})
+
+	f, err := injector.InvokePartial(func(db string, n int, c *Config, tags ...string) string {
+		return fmt.Sprintf("%s %d %s %s", db, n, c.Name, strings.Join(tags, ","))
+	})
+	expect(t, err, nil)
+	partial, ok := f.(func(int, ...string) string)
+	expect(t, ok, true)
+	expect(t, partial(2, "a", "b"), "db 2 cfg a,b")
+
+	f, err = injector.InvokePartial(func(int, string) {})
+	expect(t, err, nil)
+	_, ok = f.(func(int))
+	expect(t, ok, true)
+
+	boom := errors.New("boom")
+	injector.Provide(func() (float64, error) { return 0, boom })
+	_, err = injector.InvokePartial(func(float64) {})
+	expect(t, errors.Is(err, boom), true)
+}
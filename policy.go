@@ -0,0 +1,58 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Policy is a rule on the dependencies of the consumers of an injector,
+// e.g. an architectural layering rule. It returns an error to reject the
+// injection of the dependency described by target, see Policies.
+type Policy func(target Target) error
+
+// Policies returns an Option enforcing policies on every dependency the
+// injector resolves for a function argument or a struct field, including
+// those of its providers: a rejected dependency fails the injection
+// without being resolved. Validate reports the dependencies of its targets
+// and of the providers that the policies reject, so that the rules are
+// enforced in CI as well. Children inherit the policies.
+func Policies(policies ...Policy) Option {
+	return func(i *injector) {
+		i.policies = append(i.policies, policies...)
+	}
+}
+
+// Forbid returns a Policy rejecting the dependencies of the given types
+// for the functions and structs declared in the package whose import path
+// is pkg or in its subpackages, e.g. to enforce that handlers go through a
+// repository instead of depending on *sql.DB directly.
+func Forbid(pkg string, types ...reflect.Type) Policy {
+	forbidden := Types(types...)
+	return func(t Target) error {
+		if (t.Package == pkg || strings.HasPrefix(t.Package, pkg+"/")) && forbidden(t.Type) {
+			return fmt.Errorf("Package %s may not depend on %v", pkg, t.Type)
+		}
+		return nil
+	}
+}
+
+// enforce returns the error of the first policy of the injector rejecting
+// the dependency c is resolving.
+func (i *injector) enforce(c *call) error {
+	if len(i.policies) == 0 {
+		return nil
+	}
+	return i.admit(c.target())
+}
+
+// admit returns the error of the first policy of the injector rejecting
+// the dependency described by t.
+func (i *injector) admit(t Target) error {
+	for _, p := range i.policies {
+		if err := p(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
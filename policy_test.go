@@ -0,0 +1,53 @@
+package inject_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type policyDB struct{}
+
+type policyRepo struct{}
+
+type policyHandler struct {
+	DB *policyDB `inject`
+}
+
+func Test_InjectorPolicies(t *testing.T) {
+	db := reflect.TypeOf(&policyDB{})
+	injector := inject.New(inject.Policies(inject.Forbid("github.com/codegangsta/inject_test", db)))
+	injector.Map(&policyDB{})
+	injector.Provide(func(*policyDB) *policyRepo { return &policyRepo{} })
+
+	err := injector.Apply(&policyHandler{})
+	var ie *inject.InjectionError
+	expect(t, errors.As(err, &ie), true)
+	expect(t, err.Error(), "Cannot inject DB *inject_test.policyDB of inject_test.policyHandler: Package github.com/codegangsta/inject_test may not depend on *inject_test.policyDB")
+
+	_, err = injector.Invoke(func(*policyRepo) {})
+	expect(t, strings.Contains(err.Error(), "may not depend on *inject_test.policyDB"), true)
+
+	err = injector.Validate(&policyHandler{}, func(*policyDB) {})
+	expect(t, len(strings.Split(err.Error(), "\n")), 3)
+
+	allowed := inject.New(inject.Policies(inject.Forbid("example.com/handlers", db)))
+	allowed.Map(&policyDB{})
+	expect(t, allowed.Apply(&policyHandler{}), nil)
+	expect(t, allowed.Validate(&policyHandler{}), nil)
+
+	var seen inject.Target
+	child := injector.Child(inject.Policies(func(t inject.Target) error {
+		seen = t
+		return nil
+	}))
+	_, err = child.Invoke(func(*policyDB) {})
+	expect(t, err != nil, true)
+	expect(t, seen.Package, "")
+	_, err = child.Invoke(func(int) {})
+	expect(t, seen.Package, "github.com/codegangsta/inject_test")
+	expect(t, seen.Type, reflect.TypeOf(0))
+}
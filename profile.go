@@ -0,0 +1,75 @@
+package inject
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultProfile is activated by ActivateFromEnv when the environment
+// variable does not name any profile.
+const DefaultProfile = "default"
+
+// Profile registers setup as the bindings of the named profile. setup is
+// not called before the profile is activated.
+func (i *injector) Profile(name string, setup func(TypeMapper)) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.profiles == nil {
+		i.profiles = make(map[string]func(TypeMapper))
+	}
+	i.profiles[name] = setup
+}
+
+// Activate layers the bindings of the named profiles on top of the
+// bindings of the injector. Profiles are applied in the given order, so
+// bindings of a later profile override those of the injector and of the
+// earlier profiles, e.g. Activate("cloud", "prod"). The bindings of the
+// profiles are published at once when every setup returned, so that no
+// resolution sees a partially activated profile, nor do the setups.
+// Returns an error without applying any profile if one of them is unknown.
+func (i *injector) Activate(names ...string) error {
+	setups := make([]func(TypeMapper), len(names))
+	i.mu.Lock()
+	for n, name := range names {
+		setups[n] = i.profiles[name]
+	}
+	i.mu.Unlock()
+
+	for n, setup := range setups {
+		if setup == nil {
+			return fmt.Errorf("Unknown profile %q", names[n])
+		}
+	}
+	i.stage(func() {
+		for _, setup := range setups {
+			setup(i)
+		}
+	})
+	i.mu.Lock()
+	i.active = append(i.active, names...)
+	i.mu.Unlock()
+	return nil
+}
+
+// ActivateFromEnv activates the profiles listed, separated by commas, in
+// the environment variable key. It activates DefaultProfile, if one is
+// registered, when the variable is empty or unset.
+func (i *injector) ActivateFromEnv(key string) error {
+	var names []string
+	for _, name := range strings.Split(os.Getenv(key), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		i.mu.Lock()
+		_, ok := i.profiles[DefaultProfile]
+		i.mu.Unlock()
+		if !ok {
+			return nil
+		}
+		names = []string{DefaultProfile}
+	}
+	return i.Activate(names...)
+}
@@ -0,0 +1,58 @@
+package inject_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorActivateFromEnv(t *testing.T) {
+	newInjector := func() inject.Injector {
+		injector := inject.New()
+		injector.Map("base").Map(1)
+		injector.Profile(inject.DefaultProfile, func(m inject.TypeMapper) { m.Map("dev") })
+		injector.Profile("prod", func(m inject.TypeMapper) { m.Map("prod").Map(2) })
+		injector.Profile("eu", func(m inject.TypeMapper) { m.Map("prod-eu") })
+		return injector
+	}
+	get := func(injector inject.Injector) (string, int) {
+		return injector.Get(reflect.TypeOf("")).String(), injector.Get(reflect.TypeOf(0)).Interface().(int)
+	}
+	defer os.Unsetenv("INJECT_TEST_ENV")
+
+	os.Unsetenv("INJECT_TEST_ENV")
+	injector := newInjector()
+	expect(t, injector.ActivateFromEnv("INJECT_TEST_ENV"), nil)
+	s, n := get(injector)
+	expect(t, s, "dev")
+	expect(t, n, 1)
+
+	os.Setenv("INJECT_TEST_ENV", "prod, eu")
+	injector = newInjector()
+	expect(t, injector.ActivateFromEnv("INJECT_TEST_ENV"), nil)
+	s, n = get(injector)
+	expect(t, s, "prod-eu")
+	expect(t, n, 2)
+
+	os.Setenv("INJECT_TEST_ENV", "prod,staging")
+	injector = newInjector()
+	refute(t, injector.ActivateFromEnv("INJECT_TEST_ENV"), nil)
+	s, _ = get(injector)
+	expect(t, s, "base")
+}
+
+func Test_InjectorActivatePublishesOnce(t *testing.T) {
+	injector := inject.New()
+	injector.Map("base").Map(1)
+	injector.Profile("prod", func(m inject.TypeMapper) {
+		m.Map("prod")
+		// nothing is published before every setup returned
+		expect(t, m.Get(reflect.TypeOf("")).String(), "base")
+		m.Map(2)
+	})
+	expect(t, injector.Activate("prod"), nil)
+	expect(t, injector.Get(reflect.TypeOf("")).String(), "prod")
+	expect(t, injector.Get(reflect.TypeOf(0)).Interface(), 2)
+}
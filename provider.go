@@ -0,0 +1,237 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// provider is a function building the value of a type. The arguments of fn
+// are its dependencies.
+type provider struct {
+	fn reflect.Value
+	// transient providers are invoked from the injector the resolution
+	// started from, every time their type is resolved.
+	transient bool
+
+	// mu guards the memoized result of non-transient providers.
+	mu   sync.Mutex
+	done bool
+	val  reflect.Value
+	// holders are the injectors holding a reference to the value of a
+	// Shared binding.
+	holders map[*injector]bool
+	// weak points to the value of a Weak binding.
+	weak weakPointer
+	// targeted providers take a Target, their values are memoized by
+	// consumer in consumers.
+	targeted  bool
+	consumers map[Target]reflect.Value
+	// keyed holds the values of a KeyedBy binding by key.
+	keyed map[interface{}]reflect.Value
+	// expires is when the value of a binding rotated with Rotate expires,
+	// refreshing whether a goroutine builds the next one.
+	expires    time.Time
+	refreshing bool
+}
+
+// Maps the first return type of provider to a value built lazily by
+// invoking provider. A second return value of type error is reported by
+// Apply, Invoke and Construct when the provider fails. A value mapped to
+// the type takes precedence over the provider, whichever is registered
+// first.
+// It panics if provider is not a function returning at least one value.
+func (i *injector) Provide(provider interface{}, opts ...BindOption) TypeMapper {
+	i.addProvider(provider, false, "Provide", opts)
+	return i
+}
+
+func (i *injector) addProvider(fn interface{}, transient bool, caller string, opts []BindOption) *provider {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.Type().NumOut() == 0 {
+		panic("Called inject." + caller + " with a value that is not a function returning a value")
+	}
+	p := &provider{fn: v, transient: transient, targeted: takes(v.Type(), targetType)}
+	i.bind(v.Type().Out(0), newBinding(reflect.Value{}, p, opts))
+	return p
+}
+
+// Transient returns a BindOption invoking the provider of the binding for
+// every resolution of its type instead of once, from the injector the
+// resolution started from. Values built by transient providers are not
+// closed by Dispose, nor passed to OnDestroy hooks, except by the scopes
+// returned by Enter.
+// Transient has no effect on mapped values.
+func Transient() BindOption {
+	return func(e *binding) {
+		if e.provider != nil {
+			e.provider.transient = true
+		}
+	}
+}
+
+// Invalidate forgets the value built by the memoized provider of t, so that
+// the next resolution of t builds it again, e.g. once the configuration it
+// was built from changed. Values resolved before keep being used by their
+// consumers, and the forgotten value is still closed by Dispose.
+// Returns an error if t is not bound to a memoized provider of the
+// injector, or to a Shared one, which is built again once released.
+func (i *injector) Invalidate(t reflect.Type) error {
+	e, ok := i.snapshot().entry(t)
+	switch {
+	case !ok || e.provider == nil:
+		return fmt.Errorf("Type %v is not provided by the injector", t)
+	case e.provider.transient:
+		return fmt.Errorf("Type %v is provided by a transient provider", t)
+	case e.shared:
+		return fmt.Errorf("Type %v is provided by a Shared provider", t)
+	}
+	p := e.provider
+	p.mu.Lock()
+	p.val, p.done = reflect.Value{}, false
+	p.weak = weakPointer{}
+	p.consumers, p.keyed = nil, nil
+	p.mu.Unlock()
+	return nil
+}
+
+// provide invokes the provider of e to build the value of t as part of c.
+// Memoized providers are invoked from the injector they belong to, once.
+// Transient providers are invoked from the injector c started from.
+func (i *injector) provide(t reflect.Type, e *binding, c *call) (reflect.Value, error) {
+	p := e.provider
+	if c.providing[p] {
+		return reflect.Value{}, &CycleError{t}
+	}
+	c.share()
+	c.providing[p] = true
+	defer delete(c.providing, p)
+
+	if c.dry {
+		return i.rehearse(t, e, c)
+	}
+	if p.transient {
+		if err := c.done(t); err != nil {
+			return reflect.Value{}, err
+		}
+		val, fresh, err := c.origin.build(t, e, c)
+		if err != nil || !fresh {
+			return val, err
+		}
+		if err := created(t, e, val); err != nil {
+			return reflect.Value{}, err
+		}
+		// only a scope, whose lifetime is bounded, tears transient values
+		// down, except those the cache keeps beyond it
+		if c.origin.entered && e.cache == nil {
+			c.origin.track(val.Interface())
+			c.origin.destroyed(e, val)
+		}
+		return val, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.targeted {
+		return i.provideTargeted(t, e, c)
+	}
+	if e.keys != nil {
+		return i.provideKeyed(t, e, c)
+	}
+	if weakly(t, e) {
+		return i.provideWeak(t, e, c)
+	}
+	i.expire(t, e)
+	if !p.done {
+		if err := c.done(t); err != nil {
+			return reflect.Value{}, err
+		}
+		val, fresh, err := i.build(t, e, c.at(i))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if fresh {
+			if err := created(t, e, val); err != nil {
+				return reflect.Value{}, err
+			}
+			if !e.shared {
+				i.track(val.Interface())
+				i.destroyed(e, val)
+			}
+		}
+		p.val, p.done = val, true
+		i.rotate(t, e)
+	}
+	if e.shared {
+		c.origin.acquire(e)
+	}
+	return p.val, nil
+}
+
+// rehearse resolves, as part of the dry call c, what the provider of e
+// would be invoked with to build the value of t, and returns the zero
+// value of t instead of invoking it.
+func (i *injector) rehearse(t reflect.Type, e *binding, c *call) (reflect.Value, error) {
+	from, at := c.origin, c
+	if !e.provider.transient {
+		from, at = i, c.at(i)
+	}
+	if _, err := from.arguments(e.provider.fn.Interface(), at, nil); err != nil {
+		return reflect.Value{}, &ProviderError{t, err}
+	}
+	if !e.provider.transient {
+		for _, kt := range e.keys {
+			if _, err := c.origin.lookup(kt, c); err != nil {
+				return reflect.Value{}, &ProviderError{t, fmt.Errorf("Cannot resolve cache key %v: %w", kt, err)}
+			}
+		}
+	}
+	return reflect.Zero(t), nil
+}
+
+// build invokes the provider of e from the injector. Its arguments are
+// resolved before a construction slot is taken, so that providers
+// depending on each other do not exhaust the slots. The boolean is false
+// when the value is served by the cache of the binding, see CacheLRU, in
+// which case it belongs to the resolution that built it.
+func (i *injector) build(t reflect.Type, e *binding, c *call) (reflect.Value, bool, error) {
+	p := e.provider
+	defer c.serialize()()
+	guards := len(c.guards)
+	in, err := i.arguments(p.fn.Interface(), c, nil)
+	if err != nil {
+		return reflect.Value{}, false, &ProviderError{t, err}
+	}
+
+	key, cached := e.cache.key(in)
+	if cached {
+		if val, ok := e.cache.get(key); ok {
+			return val, false, nil
+		}
+	}
+
+	// the breaker is consulted last, every invocation it lets through
+	// being recorded
+	if err := e.breaker.allow(t); err != nil {
+		return reflect.Value{}, false, &ProviderError{t, err}
+	}
+	var start time.Time
+	if c.observe != nil {
+		start = time.Now()
+	}
+	val, err := i.attempt(t, e, in, c)
+	if c.observe != nil {
+		c.observe(t, time.Since(start))
+	}
+	c.verify(guards, p.fn)
+	e.breaker.record(err)
+	if err != nil {
+		return reflect.Value{}, false, &ProviderError{t, err}
+	}
+	if cached {
+		e.cache.put(key, val)
+	}
+	c.advance(t, "")
+	return val, true, nil
+}
@@ -0,0 +1,39 @@
+package inject
+
+import "reflect"
+
+// MapRef binds typ to a live reference into other: every resolution of typ
+// resolves it from other at that time, so that the values of a shared
+// platform injector referenced by many application injectors are never
+// copied and follow the bindings of other as they change. Memoized
+// providers of other are built and owned by other. When other cannot
+// resolve typ, the resolution goes on as if typ was not bound; when
+// references lead back to the binding, it fails with a CycleError.
+// It panics if other is the injector itself.
+func (i *injector) MapRef(other Injector, typ reflect.Type, opts ...BindOption) TypeMapper {
+	if other == Injector(i) {
+		panic("Called inject.MapRef with the injector itself")
+	}
+	e := newBinding(reflect.Value{}, nil, opts)
+	e.ref = other
+	i.bind(typ, e)
+	return i
+}
+
+// lookupRef resolves t from the injector the binding e refers to, as part
+// of c. Returns a CycleError if the resolution of t through e leads back
+// to e, e.g. through references between injectors into each other.
+func (i *injector) lookupRef(t reflect.Type, e *binding, c *call) (reflect.Value, error) {
+	if c.referring[e] {
+		return reflect.Value{}, &CycleError{t}
+	}
+	if c.referring == nil {
+		c.referring = make(map[*binding]bool)
+	}
+	c.referring[e] = true
+	defer delete(c.referring, e)
+	if o, ok := e.ref.(*injector); ok {
+		return o.lookup(t, c.at(o))
+	}
+	return delegate(e.ref, t, c)
+}
@@ -0,0 +1,73 @@
+package inject_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type platformConfig struct {
+	region string
+}
+
+func Test_InjectorMapRef(t *testing.T) {
+	platform := inject.New()
+	calls := 0
+	platform.Provide(func() *platformConfig {
+		calls++
+		return &platformConfig{"eu"}
+	})
+
+	typ := reflect.TypeOf(&platformConfig{})
+	a, b := inject.New(), inject.New()
+	a.MapRef(platform, typ)
+	b.MapRef(platform, typ)
+	expect(t, a.Get(typ).Interface(), b.Get(typ).Interface())
+	expect(t, calls, 1)
+	expect(t, a.Validate(func(*platformConfig) {}), nil)
+
+	// the reference follows the bindings of the platform
+	platform.Map(&platformConfig{"us"})
+	_, err := a.Invoke(func(c *platformConfig) {
+		expect(t, c.region, "us")
+	})
+	expect(t, err, nil)
+
+	missing := reflect.TypeOf("")
+	a.MapRef(platform, missing)
+	expect(t, a.Get(missing).IsValid(), false)
+	expect(t, a.Validate(func(string) {}) != nil, true)
+	other := inject.New()
+	other.Map(7)
+	a.Map(42)
+	a.MapRef(other, reflect.TypeOf(0))
+	expect(t, a.Get(reflect.TypeOf(0)).Int(), int64(7))
+}
+
+func Test_InjectorMapRefPanics(t *testing.T) {
+	defer func() {
+		expect(t, recover(), "Called inject.MapRef with the injector itself")
+	}()
+	injector := inject.New()
+	injector.MapRef(injector, reflect.TypeOf(""))
+}
+
+func Test_InjectorMapRefCycle(t *testing.T) {
+	typ := reflect.TypeOf("")
+	a, b := inject.New(), inject.New()
+	a.MapRef(b, typ)
+	b.MapRef(a, typ)
+	_, err := a.Invoke(func(string) {})
+	expect(t, errors.Is(err, inject.ErrCycle), true)
+	expect(t, errors.Is(a.Validate(func(string) {}), inject.ErrCycle), true)
+
+	app := inject.New()
+	req := app.Child()
+	app.MapRef(req, typ)
+	_, err = req.Invoke(func(string) {})
+	expect(t, errors.Is(err, inject.ErrCycle), true)
+	expect(t, app.Get(typ).IsValid(), false)
+	expect(t, errors.Is(app.Validate(func(string) {}), inject.ErrCycle), true)
+}
@@ -0,0 +1,38 @@
+package inject
+
+import "sync"
+
+var registry struct {
+	sync.Mutex
+	modules []func(TypeMapper)
+}
+
+// Register records a module setting up bindings, to be applied by every
+// later call to BuildRegistered. Packages call it from init so that
+// importing them for their side effects is enough to make their drivers,
+// codecs or handlers available, in the style of database/sql drivers.
+// It panics if module is nil.
+func Register(module func(TypeMapper)) {
+	if module == nil {
+		panic("Called inject.Register with a nil module")
+	}
+	registry.Lock()
+	registry.modules = append(registry.modules, module)
+	registry.Unlock()
+}
+
+// BuildRegistered returns a new injector configured by opts, with the
+// bindings of every module recorded by Register, applied in the order they
+// were registered.
+func BuildRegistered(opts ...Option) Injector {
+	registry.Lock()
+	modules := make([]func(TypeMapper), len(registry.modules))
+	copy(modules, registry.modules)
+	registry.Unlock()
+
+	inj := New(opts...)
+	for _, module := range modules {
+		module(inj)
+	}
+	return inj
+}
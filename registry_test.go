@@ -0,0 +1,29 @@
+package inject_test
+
+import (
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type Driver string
+
+func init() {
+	inject.Register(func(m inject.TypeMapper) {
+		m.Map(Driver("registered"))
+	})
+}
+
+func Test_BuildRegistered(t *testing.T) {
+	a := inject.BuildRegistered()
+	b := inject.BuildRegistered(inject.TagName("di"))
+
+	_, err := a.Invoke(func(d Driver) { expect(t, d, Driver("registered")) })
+	expect(t, err, nil)
+	_, err = b.Invoke(func(d Driver) { expect(t, d, Driver("registered")) })
+	expect(t, err, nil)
+
+	a.Map(Driver("changed"))
+	_, err = b.Invoke(func(d Driver) { expect(t, d, Driver("registered")) })
+	expect(t, err, nil)
+}
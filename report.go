@@ -0,0 +1,176 @@
+package inject
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// WiringReport is the outcome of a dry run of the construction of an
+// application, see BuildReport.
+type WiringReport struct {
+	// Steps are the providers of the injector and its parents in the order
+	// they would be built, every provider after those it depends on.
+	Steps []BuildStep
+	// Targets holds, for every target BuildReport was given in order, how
+	// each of its dependencies would be injected.
+	Targets [][]Explanation
+	// Deferred are the types bound with MapDeferred once the injector is
+	// sealed, by name.
+	Deferred []reflect.Type
+	// Disposers is the number of functions registered with OnDispose so
+	// far.
+	Disposers int
+	// Problems are the problems Validate would report.
+	Problems []error
+}
+
+// BuildStep is a provider of a WiringReport.
+type BuildStep struct {
+	Binding
+	// Lifecycle lists the options of the binding affecting when its value
+	// is built and released: "transient", "shared", "weak", "rotated",
+	// "per consumer", "keyed", the hooks registered as "OnCreate",
+	// "OnDestroy" and "OnExpire", and "Close" for a value closed by
+	// Dispose.
+	Lifecycle []string
+}
+
+// BuildReport simulates the construction of the application wired in the
+// injector, calling no function or provider: it reports the order the
+// providers of the injector and its parents would be built in, how the
+// dependencies of every target, a function or a struct as accepted by
+// Validate, would be injected, the lifecycle registered and every problem
+// Validate would find, so that CI can check the production wiring it
+// cannot run. Unlike Validate, BuildReport does not seal the injector nor
+// record the bindings it reaches as used, see Unused.
+func (i *injector) BuildReport(targets ...interface{}) WiringReport {
+	var r WiringReport
+	nodes, entries := i.nodes()
+	for _, k := range buildOrder(nodes) {
+		r.Steps = append(r.Steps, BuildStep{nodes[k].Binding, lifecycle(nodes[k].Type, entries[k])})
+	}
+
+	for _, target := range targets {
+		t := reflect.TypeOf(target)
+		var explanations []Explanation
+		if t != nil && t.Kind() == reflect.Func {
+			explanations = i.ExplainInvoke(target)
+		} else {
+			explanations, _ = i.ExplainApply(target)
+		}
+		r.Targets = append(r.Targets, explanations)
+	}
+
+	i.mu.Lock()
+	for _, d := range i.deferred {
+		r.Deferred = append(r.Deferred, d.typ)
+	}
+	r.Disposers = len(i.disposers)
+	i.mu.Unlock()
+	sortTypes(r.Deferred)
+
+	r.Problems = i.problems(targets, false)
+	return r
+}
+
+// buildOrder returns the indexes of the provided nodes, every node after
+// the first node bound to each of its dependencies. Nodes of a cycle are
+// ordered arbitrarily.
+func buildOrder(nodes []Node) []int {
+	bound := make(map[reflect.Type]int)
+	for k := len(nodes) - 1; k >= 0; k-- {
+		if nodes[k].Name == "" {
+			bound[nodes[k].Type] = k
+		}
+	}
+
+	var order []int
+	visited := make([]bool, len(nodes))
+	var visit func(int)
+	visit = func(k int) {
+		if visited[k] {
+			return
+		}
+		visited[k] = true
+		for _, dep := range nodes[k].Dependencies {
+			if d, ok := bound[dep]; ok {
+				visit(d)
+			}
+		}
+		if nodes[k].Provided {
+			order = append(order, k)
+		}
+	}
+	for k := range nodes {
+		if nodes[k].Provided {
+			visit(k)
+		}
+	}
+	return order
+}
+
+var closerType = reflect.TypeOf((*io.Closer)(nil)).Elem()
+
+// lifecycle describes the lifecycle of the binding e of t, see BuildStep.
+func lifecycle(t reflect.Type, e *binding) []string {
+	p := e.provider
+	var l []string
+	for _, opt := range []struct {
+		set  bool
+		name string
+	}{
+		{p.transient, "transient"},
+		{e.shared, "shared"},
+		{e.weak, "weak"},
+		{e.rotation != nil, "rotated"},
+		{p.targeted, "per consumer"},
+		{e.keys != nil, "keyed"},
+		{len(e.onCreate) > 0, "OnCreate"},
+		{len(e.onDestroy) > 0, "OnDestroy"},
+		{len(e.onExpire) > 0, "OnExpire"},
+		{!p.transient && t.Implements(closerType), "Close"},
+	} {
+		if opt.set {
+			l = append(l, opt.name)
+		}
+	}
+	return l
+}
+
+// String formats the report for a build log.
+func (r WiringReport) String() string {
+	var b strings.Builder
+	b.WriteString("build order:\n")
+	for n, s := range r.Steps {
+		fmt.Fprintf(&b, "  %d. %s", n+1, Node{Binding: s.Binding}.label())
+		if s.Scope != "" {
+			fmt.Fprintf(&b, " in scope %s", s.Scope)
+		}
+		if s.Source != "" {
+			fmt.Fprintf(&b, " at %s", s.Source)
+		}
+		if len(s.Lifecycle) > 0 {
+			fmt.Fprintf(&b, ": %s", strings.Join(s.Lifecycle, ", "))
+		}
+		b.WriteString("\n")
+	}
+	for n, explanations := range r.Targets {
+		fmt.Fprintf(&b, "target %d:\n", n)
+		for _, e := range explanations {
+			fmt.Fprintf(&b, "  %v\n", e)
+		}
+	}
+	if len(r.Deferred) > 0 {
+		fmt.Fprintf(&b, "deferred: %v\n", r.Deferred)
+	}
+	fmt.Fprintf(&b, "disposers: %d\n", r.Disposers)
+	if len(r.Problems) > 0 {
+		b.WriteString("problems:\n")
+		for _, err := range r.Problems {
+			fmt.Fprintf(&b, "  %v\n", err)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package inject_test
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type reportDB struct{}
+
+func (*reportDB) Close() error { return nil }
+
+type reportRepo struct{}
+
+type reportHandler struct {
+	Repo *reportRepo `inject`
+	Port int         `inject`
+}
+
+func Test_InjectorBuildReport(t *testing.T) {
+	app := inject.New()
+	app.SetScope(inject.Singleton)
+	built := false
+	app.Provide(func(*reportDB) *reportRepo {
+		built = true
+		return &reportRepo{}
+	})
+	app.Provide(func() *reportDB {
+		built = true
+		return &reportDB{}
+	}, inject.OnDestroy(func(interface{}) error { return nil }))
+	app.MapDeferred(reflect.TypeOf(""), func(inject.Injector) (interface{}, error) {
+		return nil, errors.New("not sealed")
+	})
+	app.OnDispose(func() error { return nil })
+
+	r := app.BuildReport(&reportHandler{}, func(io.Closer) {})
+	expect(t, built, false)
+	expect(t, len(r.Steps), 2)
+	expect(t, r.Steps[0].Type, reflect.TypeOf(&reportDB{}))
+	expect(t, strings.Join(r.Steps[0].Lifecycle, ","), "OnDestroy,Close")
+	expect(t, r.Steps[1].Type, reflect.TypeOf(&reportRepo{}))
+	expect(t, r.Steps[1].Scope, inject.Singleton)
+	expect(t, len(r.Targets), 2)
+	expect(t, r.Targets[0][0].Source, inject.FromProvider)
+	expect(t, r.Targets[0][1].Source, inject.Unresolved)
+	expect(t, r.Deferred[0], reflect.TypeOf(""))
+	expect(t, r.Disposers, 1)
+	expect(t, len(r.Problems), 2)
+
+	s := r.String()
+	expect(t, strings.HasPrefix(s, "build order:\n  1. *inject_test.reportDB in scope singleton at report_test.go:"), true)
+	expect(t, strings.Contains(s, ": OnDestroy, Close\n  2. *inject_test.reportRepo"), true)
+	expect(t, strings.Contains(s, "target 1:\n  #0 io.Closer: unresolved"), true)
+	expect(t, strings.Contains(s, "deferred: [string]\ndisposers: 1\nproblems:\n"), true)
+}
+
+func Test_InjectorBuildReportUsage(t *testing.T) {
+	injector := inject.New()
+	injector.Map(&reportDB{})
+	injector.Provide(func(*reportDB) *reportRepo { return &reportRepo{} })
+
+	expect(t, len(injector.Unused()), 2)
+	r := injector.BuildReport(func(*reportRepo) {})
+	expect(t, len(r.Problems), 0)
+	expect(t, len(injector.Unused()), 2)
+
+	expect(t, injector.Validate(func(*reportRepo) {}), nil)
+	expect(t, len(injector.Unused()), 0)
+}
@@ -0,0 +1,112 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// retry is the retry policy of a binding.
+type retry struct {
+	attempts int
+	backoff  func(attempt int) time.Duration
+}
+
+// Retry returns a BindOption calling the provider of the binding up to
+// attempts times until it succeeds, which suits providers connecting to a
+// backend that may not be up yet. backoff returns the delay before the
+// next attempt given the number of the attempt that failed, starting at 1;
+// a nil backoff retries immediately. Waiting is cut short when the context
+// of the resolution is done, see Warm and InvokeWithContext.
+// Only the provider function is called again: its arguments are resolved
+// once. When every attempt fails the error of the provider is a
+// RetryError holding the error of each attempt.
+// It panics if attempts is not positive.
+func Retry(attempts int, backoff func(attempt int) time.Duration) BindOption {
+	if attempts <= 0 {
+		panic("Called inject.Retry with a number of attempts that is not positive")
+	}
+	return func(e *binding) {
+		e.retry = &retry{attempts, backoff}
+	}
+}
+
+// ExponentialBackoff returns a backoff for Retry doubling the delay after
+// every attempt, starting from base and never exceeding max.
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		d := base
+		for n := 1; n < attempt && d < max; n++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// RetryError is the error of a provider whose attempts all failed. Errs
+// holds the error of every attempt, in order; errors.Is and errors.As
+// match any of them.
+type RetryError struct {
+	Errs []error
+}
+
+func (e *RetryError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for n, err := range e.Errs {
+		msgs[n] = fmt.Sprintf("attempt %d: %v", n+1, err)
+	}
+	return fmt.Sprintf("%d attempts failed: %s", len(e.Errs), strings.Join(msgs, "; "))
+}
+
+func (e *RetryError) Unwrap() []error {
+	return e.Errs
+}
+
+// attempt calls the provider of e with in, following its retry policy, and
+// returns the value it built.
+func (i *injector) attempt(t reflect.Type, e *binding, in []reflect.Value, c *call) (reflect.Value, error) {
+	var errs []error
+	for n := 1; ; n++ {
+		out := i.limit(e.provider.fn, in, c)
+		err := lastError(out[1:])
+		if err == nil {
+			return out[0], nil
+		}
+		errs = append(errs, err)
+		if e.retry == nil || n == e.retry.attempts {
+			break
+		}
+		if err := c.wait(t, e.retry.backoff, n); err != nil {
+			errs = append(errs, err)
+			break
+		}
+	}
+	if len(errs) == 1 {
+		return reflect.Value{}, errs[0]
+	}
+	return reflect.Value{}, &RetryError{errs}
+}
+
+// wait sleeps for the delay backoff returns after the failed attempt n of
+// building t, returning early with an error once the context of c is done.
+func (c *call) wait(t reflect.Type, backoff func(int) time.Duration, n int) error {
+	if backoff != nil {
+		if d := backoff(n); d > 0 {
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+			if c.ctx == nil {
+				<-timer.C
+			} else {
+				select {
+				case <-timer.C:
+				case <-c.ctx.Done():
+				}
+			}
+		}
+	}
+	return c.done(t)
+}
@@ -0,0 +1,63 @@
+package inject_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorRetry(t *testing.T) {
+	calls := 0
+	injector := inject.New()
+	injector.Provide(func() (int, error) {
+		calls++
+		if calls < 3 {
+			return 0, errors.New("not yet")
+		}
+		return calls, nil
+	}, inject.Retry(3, inject.ExponentialBackoff(time.Millisecond, 2*time.Millisecond)))
+
+	var n int
+	expect(t, injector.Populate(&n), nil)
+	expect(t, n, 3)
+}
+
+func Test_InjectorRetryExhausted(t *testing.T) {
+	boom := errors.New("boom")
+	calls := 0
+	injector := inject.New()
+	injector.Provide(func() (int, error) {
+		calls++
+		return 0, boom
+	}, inject.Retry(2, nil))
+
+	_, err := injector.Invoke(func(int) {})
+	expect(t, calls, 2)
+	expect(t, errors.Is(err, boom), true)
+	var re *inject.RetryError
+	expect(t, errors.As(err, &re), true)
+	expect(t, len(re.Errs), 2)
+}
+
+func Test_InjectorRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	injector := inject.New()
+	injector.Provide(func() (int, error) {
+		cancel()
+		return 0, errors.New("down")
+	}, inject.Retry(5, func(int) time.Duration { return time.Hour }))
+
+	err := injector.(inject.Warmer).Warm(ctx)
+	expect(t, errors.Is(err, context.Canceled), true)
+}
+
+func Test_ExponentialBackoff(t *testing.T) {
+	backoff := inject.ExponentialBackoff(time.Second, 5*time.Second)
+	expect(t, backoff(1), time.Second)
+	expect(t, backoff(2), 2*time.Second)
+	expect(t, backoff(3), 4*time.Second)
+	expect(t, backoff(4), 5*time.Second)
+}
@@ -0,0 +1,132 @@
+package inject
+
+import (
+	"reflect"
+	"time"
+)
+
+// rotation configures the expiration of the value of a memoized provider,
+// see Rotate.
+type rotation struct {
+	ttl   time.Duration
+	ahead time.Duration
+}
+
+// Rotate returns a BindOption expiring the value built by the memoized
+// provider of the binding ttl after it was built, for credentials and
+// tokens that have to be rotated. With a positive ahead, a goroutine of
+// the injector builds the next value ahead of the expiration of the
+// current one, so that a resolution never waits for a fetch or gets an
+// expired secret; failures are logged and retried every quarter of ahead.
+// An expired value that has not been replaced is built again by the next
+// resolution. The goroutine is stopped by Dispose.
+// Values replaced are passed to the OnExpire hooks of the binding and not
+// closed, since consumers may still hold them; Dispose closes them all.
+// Rotate has no effect on mapped values and transient providers.
+// It panics if ttl is not positive or ahead is not shorter than ttl.
+func Rotate(ttl, ahead time.Duration) BindOption {
+	if ttl <= 0 || ahead < 0 || ahead >= ttl {
+		panic("Called inject.Rotate with a ttl that is not positive or not longer than ahead")
+	}
+	return func(e *binding) {
+		e.rotation = &rotation{ttl, ahead}
+	}
+}
+
+// OnExpire returns a BindOption calling fn with every value of the binding
+// replaced once expired, see Rotate. Errors returned by fn are logged.
+func OnExpire(fn func(val interface{}) error) BindOption {
+	return func(e *binding) {
+		e.onExpire = append(e.onExpire, fn)
+	}
+}
+
+// expire forgets the value of the memoized provider of e, built for t, if
+// it has expired. The provider lock is held.
+func (i *injector) expire(t reflect.Type, e *binding) {
+	p := e.provider
+	if e.rotation == nil || !p.done || time.Now().Before(p.expires) {
+		return
+	}
+	old := p.val
+	p.val, p.done = reflect.Value{}, false
+	i.expired(t, e, old)
+}
+
+// expired calls the OnExpire hooks of e with the value old replaced.
+func (i *injector) expired(t reflect.Type, e *binding, old reflect.Value) {
+	for _, fn := range e.onExpire {
+		if err := fn(old.Interface()); err != nil {
+			i.warnf("OnExpire hook for type %v failed: %v", t, err)
+		}
+	}
+}
+
+// rotate records that the value of the memoized provider of e, built for
+// t, has just been built and starts refreshing it if needed. The provider
+// lock is held.
+func (i *injector) rotate(t reflect.Type, e *binding) {
+	p := e.provider
+	if e.rotation == nil {
+		return
+	}
+	p.expires = time.Now().Add(e.rotation.ttl)
+	if e.rotation.ahead == 0 || p.refreshing {
+		return
+	}
+	p.refreshing = true
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	i.OnDispose(func() error {
+		close(stop)
+		<-stopped
+		return nil
+	})
+	go func() {
+		defer close(stopped)
+		i.refresh(t, e, stop)
+	}()
+}
+
+// refresh builds the value of the memoized provider of e, built for t,
+// ahead of its expiration until stop is closed. The next value is built
+// without the provider lock, which resolutions keep taking meanwhile, and
+// swapped in once built.
+func (i *injector) refresh(t reflect.Type, e *binding, stop chan struct{}) {
+	p := e.provider
+	ahead := e.rotation.ahead
+	p.mu.Lock()
+	wait := time.Until(p.expires) - ahead
+	p.mu.Unlock()
+	for {
+		timer := time.NewTimer(wait)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		val, fresh, err := i.build(t, e, newCall(i))
+		if err == nil && fresh {
+			err = created(t, e, val)
+		}
+		if err != nil {
+			i.warnf("refreshing type %v failed: %v", t, err)
+			wait = ahead / 4
+			continue
+		}
+		p.mu.Lock()
+		old, done := p.val, p.done
+		if fresh {
+			i.track(val.Interface())
+			i.destroyed(e, val)
+		}
+		p.val, p.done = val, true
+		p.expires = time.Now().Add(e.rotation.ttl)
+		wait = e.rotation.ttl - ahead
+		p.mu.Unlock()
+		if done {
+			i.expired(t, e, old)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package inject_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codegangsta/inject"
+)
+
+type token struct {
+	serial int
+}
+
+func Test_InjectorRotate(t *testing.T) {
+	var mu sync.Mutex
+	var expired []int
+	serial := 0
+	injector := inject.New()
+	injector.Provide(func() *token {
+		mu.Lock()
+		defer mu.Unlock()
+		serial++
+		return &token{serial}
+	}, inject.Rotate(10*time.Millisecond, 0), inject.OnExpire(func(v interface{}) error {
+		mu.Lock()
+		expired = append(expired, v.(*token).serial)
+		mu.Unlock()
+		return nil
+	}))
+
+	typ := reflect.TypeOf(&token{})
+	expect(t, injector.Get(typ).Interface().(*token).serial, 1)
+	expect(t, injector.Get(typ).Interface().(*token).serial, 1)
+	time.Sleep(20 * time.Millisecond)
+	expect(t, injector.Get(typ).Interface().(*token).serial, 2)
+	expect(t, len(expired), 1)
+	expect(t, expired[0], 1)
+}
+
+func Test_InjectorRotateAhead(t *testing.T) {
+	var mu sync.Mutex
+	serial := 0
+	injector := inject.New()
+	injector.Provide(func() *token {
+		mu.Lock()
+		defer mu.Unlock()
+		serial++
+		return &token{serial}
+	}, inject.Rotate(time.Hour, time.Hour-20*time.Millisecond))
+
+	typ := reflect.TypeOf(&token{})
+	expect(t, injector.Get(typ).Interface().(*token).serial, 1)
+	deadline := time.Now().Add(5 * time.Second)
+	for injector.Get(typ).Interface().(*token).serial == 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	expect(t, injector.Get(typ).Interface().(*token).serial > 1, true)
+
+	expect(t, injector.Dispose(), nil)
+	mu.Lock()
+	built := serial
+	mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	expect(t, serial, built)
+	mu.Unlock()
+}
+
+func Test_InjectorRotateAheadDoesNotBlock(t *testing.T) {
+	fetching, release := make(chan struct{}), make(chan struct{})
+	serial := 0
+	injector := inject.New()
+	injector.Provide(func() *token {
+		serial++
+		if serial == 2 {
+			close(fetching)
+			<-release
+		}
+		return &token{serial}
+	}, inject.Rotate(time.Hour, time.Hour-10*time.Millisecond))
+
+	typ := reflect.TypeOf(&token{})
+	expect(t, injector.Get(typ).Interface().(*token).serial, 1)
+	<-fetching
+	got := make(chan int)
+	go func() { got <- injector.Get(typ).Interface().(*token).serial }()
+	select {
+	case n := <-got:
+		expect(t, n, 1)
+	case <-time.After(time.Second):
+		t.Fatal("resolution waited for the fetch")
+	}
+	close(release)
+	expect(t, injector.Dispose(), nil)
+}
+
+func Test_RotatePanics(t *testing.T) {
+	defer func() {
+		expect(t, recover(), "Called inject.Rotate with a ttl that is not positive or not longer than ahead")
+	}()
+	inject.Rotate(time.Second, time.Second)
+}
@@ -0,0 +1,48 @@
+package inject
+
+import "reflect"
+
+// Sandbox returns a new injector, configured by opts, for wiring third
+// party plugin code against host. The sandbox resolves from host only the
+// allowed types, whose memoized values are built and owned by host as
+// usual, and never host itself: an Injector argument resolves to the
+// sandbox. Bindings registered in the sandbox are its own, and host's
+// bindings, groups and named bindings are neither walked by ForEach,
+// Graph or GetAll nor collected from the sandbox, so that a plugin can
+// neither see nor alter what the host does not hand out.
+func Sandbox(host Injector, allowed []reflect.Type, opts ...Option) Injector {
+	inj := New(opts...)
+	inj.SetParent(&sandbox{host, Types(allowed...)})
+	return inj
+}
+
+// sandbox is the parent of an injector created by Sandbox, wrapping the
+// host. It is not an *injector, which keeps the walkers of the parent
+// chain out of the host, and the methods the package calls on parents that
+// are not are filtered.
+type sandbox struct {
+	Injector
+	allowed func(reflect.Type) bool
+}
+
+// allows reports whether t may be resolved from the host.
+func (s *sandbox) allows(t reflect.Type) bool {
+	return t != injectorType && s.allowed(t)
+}
+
+func (s *sandbox) lookup(t reflect.Type, c *call) (reflect.Value, error) {
+	if !s.allows(t) {
+		return reflect.Value{}, ErrNotFound
+	}
+	if h, ok := s.Injector.(*injector); ok {
+		return h.lookup(t, c.at(h))
+	}
+	return delegate(s.Injector, t, c)
+}
+
+func (s *sandbox) Get(t reflect.Type) reflect.Value {
+	if !s.allows(t) {
+		return reflect.Value{}
+	}
+	return s.Injector.Get(t)
+}
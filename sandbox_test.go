@@ -0,0 +1,51 @@
+package inject_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type hostLogger struct{}
+
+type hostSecret string
+
+func Test_Sandbox(t *testing.T) {
+	host := inject.New()
+	host.SetScope(inject.Singleton)
+	calls := 0
+	host.Provide(func() *hostLogger {
+		calls++
+		return &hostLogger{}
+	})
+	host.Map(hostSecret("s3cr3t"))
+	host.MapWith(42, inject.Group("plugins"))
+
+	logger := reflect.TypeOf(&hostLogger{})
+	plugin := inject.Sandbox(host, []reflect.Type{logger, reflect.TypeOf((*inject.Injector)(nil)).Elem()})
+	_, err := plugin.Invoke(func(l *hostLogger, inj inject.Injector) {
+		expect(t, l, host.Get(logger).Interface())
+		expect(t, inj, plugin)
+	})
+	expect(t, err, nil)
+	expect(t, calls, 1)
+
+	_, err = plugin.Invoke(func(hostSecret) {})
+	expect(t, err != nil, true)
+	expect(t, plugin.GetScoped(reflect.TypeOf(hostSecret("")), inject.Singleton).IsValid(), false)
+	expect(t, plugin.GetScoped(logger, inject.Singleton).IsValid(), true)
+	expect(t, len(plugin.GetAll(reflect.TypeOf(hostSecret("")))), 0)
+	expect(t, len(plugin.(inject.Diagnostics).Graph().Nodes), 0)
+	expect(t, plugin.Validate(func(*hostLogger) {}), nil)
+	expect(t, plugin.Validate(func(hostSecret) {}) != nil, true)
+
+	var plugins struct {
+		All []int `inject:"group=plugins"`
+	}
+	expect(t, plugin.Apply(&plugins), nil)
+	expect(t, len(plugins.All), 0)
+
+	plugin.Map(hostSecret("plugin"))
+	expect(t, host.Get(reflect.TypeOf(hostSecret(""))).String(), "s3cr3t")
+}
@@ -0,0 +1,84 @@
+package inject
+
+import "reflect"
+
+// Scope names a level of an injector hierarchy, such as the application or
+// a single request.
+type Scope string
+
+// Scopes commonly found in an injector hierarchy. Any other name can be
+// used as well.
+const (
+	Singleton Scope = "singleton"
+	Session   Scope = "session"
+	Request   Scope = "request"
+)
+
+// SetScope names the scope the injector stands for.
+func (i *injector) SetScope(scope Scope) {
+	i.update(func(b *bindings) {
+		b.scope = scope
+	})
+}
+
+// Scope returns the scope set with SetScope, or an empty Scope.
+func (i *injector) Scope() Scope {
+	return i.snapshot().scope
+}
+
+// GetScoped returns the Value mapped to t as seen from the closest injector
+// of the parent chain, starting with the injector itself, whose scope is
+// scope. Bindings of injectors below that scope are ignored and providers
+// found there are memoized at that scope. Returns a zeroed Value if no
+// injector of the chain has the scope or if t cannot be resolved from it.
+func (i *injector) GetScoped(t reflect.Type, scope Scope) reflect.Value {
+	if inj := i.inScope(scope); inj != nil {
+		return inj.Get(t)
+	}
+	return reflect.Value{}
+}
+
+// inScope returns the closest injector of the parent chain whose scope is
+// scope, or nil. With several parents, the parents are searched breadth
+// first in the order they were added.
+func (i *injector) inScope(scope Scope) Injector {
+	queue := []Injector{i}
+	for len(queue) > 0 {
+		inj := queue[0]
+		queue = queue[1:]
+		if inj.Scope() == scope {
+			return inj
+		}
+		if p, ok := inj.(*injector); ok {
+			for _, link := range p.snapshot().parents {
+				queue = append(queue, link.inj)
+			}
+		}
+	}
+	return nil
+}
+
+// ScopeHandle is a child injector entered for the lifetime of a scope,
+// such as a single request or session, see Enter.
+type ScopeHandle struct {
+	Injector
+}
+
+// Enter returns a child injector, configured like Child, standing for
+// scope. Disposing it tears down everything built inside the scope: the
+// OnDestroy hooks of the values its own providers and the transient
+// providers it resolved have built run, those values implementing
+// io.Closer are closed, and the functions registered with OnDispose are
+// called, in the reverse order of construction. Values built by the
+// memoized providers of the parents remain owned by the parents.
+//
+//	req := app.Enter(inject.Request)
+//	defer req.Dispose()
+func (i *injector) Enter(scope Scope, opts ...Option) *ScopeHandle {
+	child := i.Child(opts...)
+	child.SetScope(scope)
+	if c, ok := child.(*injector); ok {
+		c.entered = true
+	}
+	return &ScopeHandle{child}
+}
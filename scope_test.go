@@ -0,0 +1,98 @@
+package inject_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorGetScoped(t *testing.T) {
+	app := inject.New()
+	app.SetScope(inject.Singleton)
+	app.Map("app dep")
+
+	request := inject.New()
+	request.SetParent(app)
+	request.SetScope(inject.Request)
+	request.Map("request dep").Map(42)
+
+	typ := reflect.TypeOf("")
+	expect(t, request.Scope(), inject.Request)
+	expect(t, request.Get(typ).String(), "request dep")
+	expect(t, request.GetScoped(typ, inject.Request).String(), "request dep")
+	expect(t, request.GetScoped(typ, inject.Singleton).String(), "app dep")
+	expect(t, request.GetScoped(reflect.TypeOf(42), inject.Singleton).IsValid(), false)
+	expect(t, request.GetScoped(typ, inject.Session).IsValid(), false)
+}
+
+type scopedResource struct {
+	name   string
+	closed *[]string
+}
+
+func (r *scopedResource) Close() error {
+	*r.closed = append(*r.closed, r.name)
+	return nil
+}
+
+func Test_InjectorEnter(t *testing.T) {
+	var closed, destroyed []string
+	app := inject.New()
+	app.Provide(func() *scopedResource {
+		return &scopedResource{"transient", &closed}
+	}, inject.Transient(), inject.OnDestroy(func(v interface{}) error {
+		destroyed = append(destroyed, v.(*scopedResource).name)
+		return nil
+	}))
+
+	req := app.Enter(inject.Request)
+	expect(t, req.Scope(), inject.Request)
+	req.Provide(func(r *scopedResource) string { return "memoized" })
+	req.OnDispose(func() error {
+		closed = append(closed, "disposer")
+		return nil
+	})
+	_, err := req.Invoke(func(s string, r *scopedResource) {})
+	expect(t, err, nil)
+
+	expect(t, req.Dispose(), nil)
+	expect(t, fmt.Sprint(closed), "[transient transient disposer]")
+	expect(t, fmt.Sprint(destroyed), "[transient transient]")
+
+	// already torn down
+	expect(t, req.Dispose(), nil)
+	expect(t, len(closed), 3)
+
+	// transient values built outside an entered scope are not closed
+	closed = nil
+	_, err = app.Invoke(func(r *scopedResource) {})
+	expect(t, err, nil)
+	expect(t, app.Dispose(), nil)
+	expect(t, len(closed), 0)
+}
+
+type scopeTenant string
+
+func Test_InjectorEnterCachedTransient(t *testing.T) {
+	var closed []string
+	app := inject.New()
+	app.Map(scopeTenant("acme"))
+	app.Provide(func(id scopeTenant) *scopedResource {
+		return &scopedResource{string(id), &closed}
+	}, inject.Transient(), inject.CacheLRU(4, 0))
+
+	var first *scopedResource
+	req := app.Enter(inject.Request)
+	expect(t, req.Populate(&first), nil)
+	expect(t, req.Dispose(), nil)
+	expect(t, len(closed), 0)
+
+	var second *scopedResource
+	req = app.Enter(inject.Request)
+	expect(t, req.Populate(&second), nil)
+	expect(t, second, first)
+	expect(t, req.Dispose(), nil)
+	expect(t, len(closed), 0)
+}
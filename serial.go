@@ -0,0 +1,119 @@
+package inject
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Serialized returns a BindOption marking the bound value as not safe for
+// concurrent use, e.g. a legacy client holding a connection, so that the
+// injector serializes its consumers: a function invoked with the value,
+// by Invoke or as a provider, holds a mutex of the binding until it
+// returns, and other functions taking the value wait meanwhile. The rest of
+// the graph stays lock free. Values resolved by Apply, Construct, Get or
+// Populate are handed out without the mutex, as nothing tells when their
+// consumers are done with them.
+// The mutex is reentrant for the goroutine holding it: a function holding
+// the value may invoke other consumers of it, which share the hold.
+// Functions taking several Serialized bindings lock them in the order of
+// their arguments, which has to be consistent to avoid deadlocks.
+func Serialized() BindOption {
+	return func(e *binding) {
+		e.serial = new(serialLock)
+	}
+}
+
+// serialLock is the mutex of a Serialized binding. A nested Invoke starts
+// a call of its own, which knows nothing of the bindings held by the outer
+// one, hence the lock is owned by a goroutine rather than by a call.
+type serialLock struct {
+	mu sync.Mutex
+	// owner is the id of the goroutine holding mu, 0 while mu is free or
+	// handed off, see handOff. depth counts the holds of the owner.
+	owner atomic.Uint64
+	depth int
+}
+
+func (l *serialLock) lock() {
+	g := goid()
+	if l.owner.Load() == g {
+		l.depth++
+		return
+	}
+	l.mu.Lock()
+	l.owner.Store(g)
+	l.depth = 1
+}
+
+func (l *serialLock) unlock() {
+	if l.depth--; l.depth == 0 {
+		l.owner.Store(0)
+		l.mu.Unlock()
+	}
+}
+
+// goid returns the id of the calling goroutine, read from the header of
+// its stack trace, e.g. "goroutine 18 [running]:".
+func goid() uint64 {
+	var buf [64]byte
+	b := bytes.TrimPrefix(buf[:runtime.Stack(buf[:], false)], []byte("goroutine "))
+	if n := bytes.IndexByte(b, ' '); n >= 0 {
+		b = b[:n]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+// hold locks the Serialized binding e for the function c is resolving the
+// arguments of, unless the call already holds it.
+func (c *call) hold(e *binding) {
+	if c.locking == 0 || c.held[e] {
+		return
+	}
+	if c.held == nil {
+		c.held = make(map[*binding]bool)
+	}
+	e.serial.lock()
+	c.held[e] = true
+	c.locked = append(c.locked, e)
+}
+
+// serialize starts the resolution of the arguments of a function by c and
+// returns the function releasing the bindings held for it once it ran.
+func (c *call) serialize() func() {
+	c.locking++
+	from := len(c.locked)
+	return func() {
+		c.locking--
+		for n := len(c.locked) - 1; n >= from; n-- {
+			delete(c.held, c.locked[n])
+			c.locked[n].serial.unlock()
+		}
+		c.locked = c.locked[:from]
+	}
+}
+
+// handOff gives up the ownership of the bindings c locked itself, which
+// stay locked until the goroutine calling adopt releases them, see
+// InvokeAsync.
+func (c *call) handOff() {
+	for _, e := range c.locked {
+		if e.serial.depth == 1 {
+			e.serial.owner.Store(0)
+		}
+	}
+}
+
+// adopt makes the calling goroutine the owner of the bindings given up by
+// handOff.
+func (c *call) adopt() {
+	g := goid()
+	for _, e := range c.locked {
+		if e.serial.owner.Load() == 0 {
+			e.serial.owner.Store(g)
+		}
+	}
+}
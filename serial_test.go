@@ -0,0 +1,124 @@
+package inject_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codegangsta/inject"
+)
+
+type legacyClient struct {
+	busy  atomic.Bool
+	calls int
+}
+
+func (c *legacyClient) do(t *testing.T) {
+	if !c.busy.CompareAndSwap(false, true) {
+		t.Error("legacyClient used concurrently")
+	}
+	c.calls++
+	c.busy.Store(false)
+}
+
+type legacyUser struct{}
+
+func Test_InjectorSerialized(t *testing.T) {
+	client := &legacyClient{}
+	injector := inject.New()
+	injector.MapWith(client, inject.Serialized())
+	// the provider and the function it is resolved for share the lock
+	injector.Provide(func(c *legacyClient) *legacyUser {
+		c.do(t)
+		return &legacyUser{}
+	}, inject.Transient())
+
+	var wg sync.WaitGroup
+	for n := 0; n < 20; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := injector.Invoke(func(c *legacyClient, u *legacyUser) {
+				c.do(t)
+			})
+			expect(t, err, nil)
+		}()
+	}
+	wg.Wait()
+	expect(t, client.calls, 40)
+
+	// a failed injection releases the lock
+	_, err := injector.Invoke(func(*legacyClient, int) {})
+	expect(t, err != nil, true)
+	_, err = injector.Invoke(func(c *legacyClient) { c.do(t) })
+	expect(t, err, nil)
+}
+
+func Test_InjectorSerializedInvokeAsync(t *testing.T) {
+	client := &legacyClient{}
+	injector := inject.New()
+	injector.MapWith(client, inject.Serialized())
+
+	started := make(chan struct{})
+	proceed := make(chan struct{})
+	fut := injector.InvokeAsync(func(c *legacyClient) {
+		close(started)
+		<-proceed
+		c.do(t)
+	})
+	<-started
+
+	// the binding stays held while the asynchronous function runs
+	invoked := make(chan struct{})
+	go func() {
+		defer close(invoked)
+		_, err := injector.Invoke(func(c *legacyClient) { c.do(t) })
+		expect(t, err, nil)
+	}()
+	select {
+	case <-invoked:
+		t.Fatal("Invoke ran while InvokeAsync held the binding")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(proceed)
+	_, err := fut.Wait(context.Background())
+	expect(t, err, nil)
+	<-invoked
+	expect(t, client.calls, 2)
+
+	// a failed injection releases the lock
+	_, err = injector.InvokeAsync(func(*legacyClient, int) {}).Wait(context.Background())
+	expect(t, err != nil, true)
+	_, err = injector.Invoke(func(c *legacyClient) { c.do(t) })
+	expect(t, err, nil)
+}
+
+func Test_InjectorSerializedNestedInvoke(t *testing.T) {
+	client := &legacyClient{}
+	injector := inject.New()
+	injector.MapWith(client, inject.Serialized())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := injector.Invoke(func(c *legacyClient) {
+			c.do(t)
+			_, err := injector.Invoke(func(c *legacyClient) { c.do(t) })
+			expect(t, err, nil)
+		})
+		expect(t, err, nil)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("nested Invoke deadlocked on the Serialized binding")
+	}
+	expect(t, client.calls, 2)
+
+	// the binding is released by the outer Invoke
+	_, err := injector.Invoke(func(c *legacyClient) { c.do(t) })
+	expect(t, err, nil)
+	expect(t, client.calls, 3)
+}
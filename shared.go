@@ -0,0 +1,63 @@
+package inject
+
+import (
+	"errors"
+	"io"
+	"reflect"
+)
+
+// Shared returns a BindOption reference counting the value built by the
+// provider of the binding. Every injector resolving the value, typically
+// the child scopes of the injector the binding belongs to, holds a
+// reference until it is disposed. The value is released, running its
+// OnDestroy hooks and closing it if it implements io.Closer, once the last
+// injector holding it is disposed, and built again by the next resolution.
+// Shared has no effect on mapped values and transient providers.
+func Shared() BindOption {
+	return func(e *binding) {
+		e.shared = true
+	}
+}
+
+// acquire records that the injector holds a reference to the value of the
+// Shared binding e, whose provider lock is held.
+func (i *injector) acquire(e *binding) {
+	p := e.provider
+	if p.holders[i] {
+		return
+	}
+	if p.holders == nil {
+		p.holders = make(map[*injector]bool)
+	}
+	p.holders[i] = true
+	i.OnDispose(func() error {
+		return i.release(e)
+	})
+}
+
+// release drops the reference the injector holds to the value of the
+// Shared binding e, releasing the value when it was the last one.
+func (i *injector) release(e *binding) error {
+	p := e.provider
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.holders, i)
+	if len(p.holders) > 0 || !p.done {
+		return nil
+	}
+
+	v := p.val.Interface()
+	p.val, p.done = reflect.Value{}, false
+	var errs []error
+	for n := len(e.onDestroy) - 1; n >= 0; n-- {
+		if err := e.onDestroy[n](v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c, ok := v.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,44 @@
+package inject_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorShared(t *testing.T) {
+	var events []string
+	built := 0
+	parent := inject.New()
+	parent.Provide(func() io.Closer {
+		built++
+		return &closer{"close", &events}
+	}, inject.Shared(), inject.OnDestroy(func(interface{}) error {
+		events = append(events, "destroy")
+		return nil
+	}))
+
+	first, second := inject.New(), inject.New()
+	first.SetParent(parent)
+	second.SetParent(parent)
+	var a, b io.Closer
+	expect(t, first.Populate(&a), nil)
+	expect(t, first.Populate(&a), nil)
+	expect(t, second.Populate(&b), nil)
+	expect(t, a == b, true)
+	expect(t, built, 1)
+
+	expect(t, first.Dispose(), nil)
+	expect(t, len(events), 0)
+	expect(t, second.Dispose(), nil)
+	expect(t, strings.Join(events, " "), "destroy close")
+	expect(t, parent.Dispose(), nil)
+	expect(t, len(events), 2)
+
+	third := inject.New()
+	third.SetParent(parent)
+	expect(t, third.Populate(&a), nil)
+	expect(t, built, 2)
+}
@@ -0,0 +1,26 @@
+package inject
+
+import "reflect"
+
+// sameSignature returns the only function type bound in the injector or its
+// parents, other than t, with the same signature as the function type t, so
+// that a func(string) error resolves from a mapped Validator defined as
+// func(string) error and the other way around. Nothing is returned when
+// several bound types share the signature.
+func (i *injector) sameSignature(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Func {
+		return nil, false
+	}
+	var from reflect.Type
+	for _, cand := range i.mappedTypes() {
+		c := cand.typ
+		if c == t || c == from || c.Kind() != reflect.Func || !c.ConvertibleTo(t) {
+			continue
+		}
+		if from != nil {
+			return nil, false
+		}
+		from = c
+	}
+	return from, from != nil
+}
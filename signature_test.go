@@ -0,0 +1,36 @@
+package inject_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type Transform func(string) string
+
+type Rewrite func(string) string
+
+func Test_InjectorFuncSignature(t *testing.T) {
+	injector := inject.New()
+	injector.Map(Transform(strings.ToUpper))
+
+	_, err := injector.Invoke(func(f func(string) string, r Rewrite) {
+		expect(t, f("a"), "A")
+		expect(t, r("b"), "B")
+	})
+	expect(t, err, nil)
+	expect(t, injector.Validate(func(func(string) string) {}), nil)
+
+	unnamed := inject.New()
+	unnamed.Map(strings.ToLower)
+	_, err = unnamed.Invoke(func(f Transform) { expect(t, f("A"), "a") })
+	expect(t, err, nil)
+
+	injector.Map(Rewrite(strings.ToLower))
+	_, err = injector.Invoke(func(func(string) string) {})
+	refute(t, err, nil)
+
+	_, err = injector.Invoke(func(func(int) string) {})
+	refute(t, err, nil)
+}
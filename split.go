@@ -0,0 +1,71 @@
+package inject
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"reflect"
+)
+
+// Split describes how BindSplit routes resolutions between a primary and
+// an alternate binding.
+type Split struct {
+	// Fraction is the share of resolutions, between 0 and 1, routed to the
+	// alternate binding.
+	Fraction float64
+	// Key, when not nil, returns the key of a resolution computed from the
+	// injector the resolution started from, e.g. a tenant or user ID mapped
+	// in a request scope. Resolutions with the same key are consistently
+	// routed to the same binding.
+	Key func(Injector) string
+	// Keys lists the keys always routed to the alternate binding.
+	Keys []string
+}
+
+// alternate reports whether a resolution from origin is routed to the
+// alternate binding.
+func (s Split) alternate(origin Injector) bool {
+	if s.Key == nil {
+		return rand.Float64() < s.Fraction
+	}
+
+	key := s.Key(origin)
+	for _, k := range s.Keys {
+		if k == key {
+			return true
+		}
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return float64(h.Sum32())/(1<<32) < s.Fraction
+}
+
+// BindSplit binds primary and alternate and routes every resolution of the
+// bound type to one of them as described by split, which enables gradual
+// rollouts of a new implementation through the injector.
+// primary and alternate are either two values of the same type or two
+// providers with identical first return types, see BindIf. Unlike BindIf,
+// the choice is made again for every resolution and providers are invoked,
+// from the injector the resolution started from, every time they are
+// chosen.
+// It panics if primary and alternate do not have the same bound type.
+func (i *injector) BindSplit(split Split, primary, alternate interface{}) TypeMapper {
+	t, err := boundType(primary, alternate)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	ft := reflect.FuncOf([]reflect.Type{callType}, []reflect.Type{t, errorType}, false)
+	i.bind(t, &binding{provider: &provider{
+		fn: reflect.MakeFunc(ft, func(in []reflect.Value) []reflect.Value {
+			c := in[0].Interface().(*call)
+			chosen := primary
+			if split.alternate(c.origin) {
+				chosen = alternate
+			}
+			val, err := evaluate(c, chosen)
+			return results(t, val, err)
+		}),
+		transient: true,
+	}})
+	return i
+}
@@ -0,0 +1,45 @@
+package inject_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type tenant string
+
+func Test_InjectorBindSplit(t *testing.T) {
+	newMemory := func() Cache { return memoryCache{} }
+	newRedis := func() Cache { return redisCache{} }
+	kind := func(inj inject.Injector) string {
+		var c Cache
+		expect(t, inj.Populate(&c), nil)
+		return c.Kind()
+	}
+
+	injector := inject.New()
+	injector.BindSplit(inject.Split{Fraction: 0}, newMemory, newRedis)
+	expect(t, kind(injector), "memory")
+
+	injector.BindSplit(inject.Split{Fraction: 1}, newMemory, newRedis)
+	expect(t, kind(injector), "redis")
+
+	byTenant := inject.Split{
+		Key: func(inj inject.Injector) string {
+			return inj.Get(reflect.TypeOf(tenant(""))).String()
+		},
+		Keys: []string{"beta"},
+	}
+	injector.BindSplit(byTenant, newMemory, newRedis)
+	for _, name := range []string{"acme", "beta", "acme"} {
+		request := inject.New()
+		request.SetParent(injector)
+		request.Map(tenant(name))
+		if name == "beta" {
+			expect(t, kind(request), "redis")
+		} else {
+			expect(t, kind(request), "memory")
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package inject
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Transaction is the scope InvokeInTx enters for every transaction.
+const Transaction Scope = "transaction"
+
+// InvokeInTx runs f as a unit of work. It begins a transaction on the
+// *sql.DB resolved from inj, enters a Transaction scope of inj, see Enter,
+// maps the *sql.Tx there and invokes f from the scope with
+// InvokeWithContext, so that repositories only have to declare a *sql.Tx
+// argument. The transaction is committed when f returns and its last
+// return value is not a non-nil error, and rolled back otherwise, including
+// when f panics. The scope is closed once the transaction is committed or
+// rolled back, which tears down the transient values built for it.
+// Returns the values returned by f, and the error of f or the error that
+// prevented the transaction from being run or committed.
+func InvokeInTx(ctx context.Context, inj Injector, opts *sql.TxOptions, f interface{}) (out []reflect.Value, err error) {
+	var db *sql.DB
+	if err := inj.Populate(&db); err != nil {
+		return nil, err
+	}
+
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot begin transaction: %w", err)
+	}
+
+	scope := inj.Enter(Transaction)
+	scope.Map(tx)
+
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+		if derr := scope.Dispose(); err == nil {
+			err = derr
+		}
+	}()
+
+	out, err = scope.InvokeWithContext(ctx, f)
+	if err == nil {
+		err = lastError(out)
+	}
+	if err != nil {
+		return out, err
+	}
+
+	committed = true
+	if cerr := tx.Commit(); cerr != nil && !errors.Is(cerr, sql.ErrTxDone) {
+		return out, fmt.Errorf("Cannot commit transaction: %w", cerr)
+	}
+	return out, nil
+}
@@ -0,0 +1,73 @@
+package inject_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+// txLog records the outcome of the transactions of the fake driver.
+var txLog []string
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { txLog = append(txLog, "commit"); return nil }
+func (fakeTx) Rollback() error { txLog = append(txLog, "rollback"); return nil }
+
+func init() {
+	sql.Register("inject-fake", fakeDriver{})
+}
+
+func Test_InvokeInTx(t *testing.T) {
+	db, err := sql.Open("inject-fake", "")
+	expect(t, err, nil)
+	defer db.Close()
+
+	injector := inject.New()
+	injector.Map(db)
+	injector.Provide(func() *session {
+		return &session{closer{"session", &txLog}}
+	}, inject.Transient())
+	ctx := context.Background()
+
+	txLog = nil
+	_, err = inject.InvokeInTx(ctx, injector, nil, func(tx *sql.Tx, c context.Context, s *session) error {
+		refute(t, tx, (*sql.Tx)(nil))
+		expect(t, c, ctx)
+		return nil
+	})
+	expect(t, err, nil)
+	expect(t, len(txLog), 2)
+	expect(t, txLog[1], "session")
+	txLog = txLog[:1]
+
+	_, err = inject.InvokeInTx(ctx, injector, nil, func(tx *sql.Tx) error {
+		return errors.New("boom")
+	})
+	expect(t, err.Error(), "boom")
+
+	_, err = inject.InvokeInTx(ctx, injector, nil, func(tx *sql.Tx, missing int) {})
+	refute(t, err, nil)
+
+	expect(t, len(txLog), 3)
+	expect(t, txLog[0], "commit")
+	expect(t, txLog[1], "rollback")
+	expect(t, txLog[2], "rollback")
+
+	_, err = inject.InvokeInTx(ctx, inject.New(), nil, func(*sql.Tx) {})
+	expect(t, errors.Is(err, inject.ErrNotFound), true)
+}
@@ -0,0 +1,106 @@
+package inject
+
+import (
+	"expvar"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// Stat holds the resolution statistics of a bound type.
+type Stat struct {
+	// Count is the number of times the type has been resolved from its
+	// binding.
+	Count uint64 `json:"count"`
+	// Last is the time of the last resolution.
+	Last time.Time `json:"last"`
+}
+
+// Statistics returns an Option making the injector count the resolutions
+// of every bound type and record the time of the last one, see Stats.
+// Without it resolutions only record whether a type is used, see Unused,
+// so that they neither read the clock nor update counters shared by every
+// goroutine resolving the type. Children inherit the option.
+func Statistics() Option {
+	return func(i *injector) {
+		i.statistics = true
+	}
+}
+
+// typeStats holds the usage of a bound type, updated without locking the
+// injector so that concurrent resolutions do not contend.
+type typeStats struct {
+	count atomic.Uint64
+	// last is the time of the last resolution in nanoseconds since the
+	// Unix epoch.
+	last atomic.Int64
+	// used tells whether the type has been resolved or reached by Validate
+	// since usage was last reset, see Unused.
+	used atomic.Bool
+}
+
+// statsOf returns the statistics of t, creating them if needed.
+func (i *injector) statsOf(t reflect.Type) *typeStats {
+	if s, ok := i.stats.Load(t); ok {
+		return s.(*typeStats)
+	}
+	s, _ := i.stats.LoadOrStore(t, new(typeStats))
+	return s.(*typeStats)
+}
+
+// markUsed records that t has been resolved, see Unused.
+func (i *injector) markUsed(t reflect.Type) {
+	i.statsOf(t).used.Store(true)
+}
+
+// resolved records a resolution of t from its binding, see Unused and
+// Stats.
+func (i *injector) resolved(t reflect.Type) {
+	s := i.statsOf(t)
+	if i.statistics {
+		s.count.Add(1)
+		s.last.Store(time.Now().UnixNano())
+	}
+	if !s.used.Load() {
+		s.used.Store(true)
+	}
+}
+
+// Stats returns the resolution statistics of every type bound in the
+// injector that has been resolved since the injector was created with the
+// Statistics option, and none without it, so that
+// operators can confirm which dependencies are exercised in production.
+// Resolutions served by a parent are counted by the parent. Unlike Unused,
+// statistics are not cleared by ResetUsage.
+func (i *injector) Stats() map[reflect.Type]Stat {
+	stats := make(map[reflect.Type]Stat)
+	i.stats.Range(func(t, v interface{}) bool {
+		if s := v.(*typeStats); s.count.Load() > 0 {
+			stats[t.(reflect.Type)] = Stat{s.count.Load(), time.Unix(0, s.last.Load())}
+		}
+		return true
+	})
+	return stats
+}
+
+// StatsVar returns an expvar.Var rendering the Stats of inj as a JSON
+// object keyed by type name, to be published with expvar.Publish.
+func StatsVar(inj Injector) expvar.Var {
+	return expvar.Func(func() interface{} {
+		return statsByName(inj)
+	})
+}
+
+// statsByName returns the Stats of inj keyed by type name, none if inj
+// does not implement Diagnostics.
+func statsByName(inj Injector) map[string]Stat {
+	stats := make(map[string]Stat)
+	d, ok := inj.(Diagnostics)
+	if !ok {
+		return stats
+	}
+	for t, s := range d.Stats() {
+		stats[t.String()] = s
+	}
+	return stats
+}
@@ -0,0 +1,87 @@
+package inject_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorStats(t *testing.T) {
+	injector := inject.New(inject.Statistics())
+	injector.Map("value").Map(42)
+
+	before := time.Now()
+	for n := 0; n < 3; n++ {
+		_, err := injector.Invoke(func(string) {})
+		expect(t, err, nil)
+	}
+
+	stats := injector.(inject.Diagnostics).Stats()
+	expect(t, len(stats), 1)
+	s := stats[reflect.TypeOf("")]
+	expect(t, s.Count, uint64(3))
+	expect(t, s.Last.Before(before), false)
+
+	injector.ResetUsage()
+	expect(t, len(injector.(inject.Diagnostics).Stats()), 1)
+
+	var decoded map[string]struct {
+		Count uint64 `json:"count"`
+	}
+	expect(t, json.Unmarshal([]byte(inject.StatsVar(injector).String()), &decoded), nil)
+	expect(t, decoded["string"].Count, uint64(3))
+}
+
+func Test_InjectorStatsConcurrent(t *testing.T) {
+	injector := inject.New(inject.Statistics())
+	injector.Map(42)
+	typ := reflect.TypeOf(42)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 100; n++ {
+				injector.Get(typ)
+			}
+		}()
+	}
+	wg.Wait()
+	expect(t, injector.(inject.Diagnostics).Stats()[typ].Count, uint64(800))
+	expect(t, len(injector.Unused()), 0)
+}
+
+func Test_InjectorStatsRebinding(t *testing.T) {
+	injector := inject.New(inject.Statistics())
+	injector.Map("first")
+	typ := reflect.TypeOf("")
+	injector.Get(typ)
+
+	injector.Map("second")
+	injector.Get(typ)
+	expect(t, injector.(inject.Diagnostics).Stats()[typ].Count, uint64(2))
+
+	// the statistics are those of the injector
+	other := inject.New(inject.Statistics())
+	expect(t, other.Get(typ).IsValid(), false)
+	expect(t, len(other.(inject.Diagnostics).Stats()), 0)
+}
+
+func Test_InjectorStatsOptIn(t *testing.T) {
+	injector := inject.New()
+	injector.Map(42)
+	typ := reflect.TypeOf(42)
+	injector.Get(typ)
+	expect(t, len(injector.(inject.Diagnostics).Stats()), 0)
+	expect(t, len(injector.Unused()), 0)
+
+	child := inject.New(inject.Statistics()).Child()
+	child.Map(42)
+	child.Get(typ)
+	expect(t, child.(inject.Diagnostics).Stats()[typ].Count, uint64(1))
+}
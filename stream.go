@@ -0,0 +1,53 @@
+package inject
+
+import (
+	"io"
+	"reflect"
+)
+
+// Connection is the scope StreamHandler enters for every connection.
+const Connection Scope = "connection"
+
+// StreamHandler adapts f to a handler of long-lived connections, such as
+// websocket or streaming RPC connections. For every connection the returned
+// handler enters a scope of inj with the Connection scope, see Enter, maps
+// the connection to its dynamic type and invokes f with injected arguments,
+// e.g. func(conn *websocket.Conn, hub *Hub, log *Logger). The scope itself
+// is mapped to Injector so that f can register per-connection resources
+// with OnDispose. The scope is closed once f returns, that is when the
+// handler is done with the connection, which tears down the transient
+// values built for the connection as well. The handler returns the error
+// of a failed injection, the last return value of f if it is a non-nil
+// error, or the error of the disposal.
+// It panics if f is not a function.
+func StreamHandler(inj Injector, f interface{}) func(conn io.Closer) error {
+	if reflect.TypeOf(f).Kind() != reflect.Func {
+		panic("Called inject.StreamHandler with a value that is not a function")
+	}
+
+	return func(conn io.Closer) (err error) {
+		scope := inj.Enter(Connection)
+		scope.Map(conn)
+		scope.MapTo(scope.Injector, (*Injector)(nil))
+		defer func() {
+			if derr := scope.Dispose(); err == nil {
+				err = derr
+			}
+		}()
+
+		out, err := scope.Invoke(f)
+		if err != nil {
+			return err
+		}
+		return lastError(out)
+	}
+}
+
+// lastError returns the last of out if it is a non-nil error.
+func lastError(out []reflect.Value) error {
+	if len(out) == 0 {
+		return nil
+	}
+	err, _ := out[len(out)-1].Interface().(error)
+	return err
+}
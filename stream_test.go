@@ -0,0 +1,49 @@
+package inject_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type conn struct {
+	closer
+}
+
+type session struct {
+	closer
+}
+
+type hub struct {
+	conns int
+}
+
+func Test_StreamHandler(t *testing.T) {
+	var closed []string
+	injector := inject.New()
+	injector.Map(&hub{})
+	injector.Provide(func() *session {
+		return &session{closer{"session", &closed}}
+	}, inject.Transient())
+
+	handler := inject.StreamHandler(injector, func(c *conn, h *hub, s *session, child inject.Injector) error {
+		h.conns++
+		child.OnDispose(func() error {
+			closed = append(closed, "connection scope")
+			return nil
+		})
+		expect(t, child.Scope(), inject.Connection)
+		return errors.New("done")
+	})
+
+	err := handler(&conn{closer{"conn", &closed}})
+	expect(t, err.Error(), "done")
+	expect(t, fmt.Sprint(closed), "[connection scope session]")
+
+	_, err = injector.Invoke(func(h *hub) {
+		expect(t, h.conns, 1)
+	})
+	expect(t, err, nil)
+}
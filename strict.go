@@ -0,0 +1,45 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// StrictApply returns an Option making Apply and ApplyWithContext return an
+// error, instead of doing nothing, when they are passed nil, a nil
+// pointer, a struct by value or anything that does not point to a struct.
+func StrictApply() Option {
+	return func(i *injector) {
+		i.strictApply = true
+	}
+}
+
+// byValue returns the error reported when a struct of type t is passed to
+// Apply by value: the fields of the copy Apply receives cannot be set, and
+// setting them would not affect the caller anyway.
+func byValue(t reflect.Type) error {
+	return fmt.Errorf("Apply requires a pointer to a struct, got a %v value whose copy cannot be injected: pass &value instead", t)
+}
+
+// applicable returns an error describing why Apply cannot inject val, if
+// val is not a non-nil pointer to a struct, possibly through several
+// pointers.
+func applicable(val interface{}) error {
+	v := reflect.ValueOf(val)
+	if !v.IsValid() {
+		return fmt.Errorf("Apply requires a pointer to a struct, got nil")
+	}
+	if v.Kind() == reflect.Struct {
+		return byValue(v.Type())
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return fmt.Errorf("Apply requires a non-nil pointer, got a nil %v", v.Type())
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("Apply requires a pointer to a struct, got %v", reflect.TypeOf(val))
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package inject_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorStrictApply(t *testing.T) {
+	type target struct {
+		S string `inject`
+	}
+	injector := inject.New(inject.StrictApply())
+	injector.Map("value")
+
+	var nilPtr *target
+	for _, c := range []struct {
+		val  interface{}
+		want string
+	}{
+		{nil, "got nil"},
+		{nilPtr, "nil *inject_test.target"},
+		{target{}, "copy cannot be injected"},
+		{42, "got int"},
+		{new(int), "got *int"},
+	} {
+		err := injector.Apply(c.val)
+		refute(t, err, nil)
+		expect(t, strings.Contains(err.Error(), c.want), true)
+	}
+
+	var s target
+	expect(t, injector.Apply(&s), nil)
+	expect(t, s.S, "value")
+	expect(t, injector.Child().Apply(42) == nil, false)
+
+	expect(t, inject.New().Apply(42), nil)
+}
+
+func Test_InjectorApplyStructValue(t *testing.T) {
+	injector := inject.New()
+	injector.Map("value")
+
+	err := injector.Apply(struct {
+		S string `inject`
+	}{})
+	refute(t, err, nil)
+	expect(t, strings.Contains(err.Error(), "pass &value instead"), true)
+}
@@ -0,0 +1,32 @@
+package inject
+
+import (
+	"reflect"
+	"sync"
+)
+
+// MapSupplier maps T to the value supply returns, calling supply once, on
+// the first resolution of T, for values that are expensive to build but
+// depend on nothing, such as compiled regular expressions or parsed
+// templates. Unlike a provider, supply takes no dependencies and goes
+// through none of the provider machinery: it is neither limited, retried
+// nor traced, and its value is not closed by Dispose. A panic of supply is
+// raised again by every resolution.
+func MapSupplier[T any](inj TypeMapper, supply func() T, opts ...BindOption) TypeMapper {
+	once := sync.OnceValue(func() reflect.Value {
+		v := supply()
+		return reflect.ValueOf(&v).Elem()
+	})
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	return inj.SetWith(t, reflect.Value{}, append(opts, func(e *binding) {
+		e.supply = once
+	})...)
+}
+
+// mapped returns the value of the mapped binding e.
+func (e *binding) mapped() reflect.Value {
+	if e.supply != nil {
+		return e.supply()
+	}
+	return e.value
+}
@@ -0,0 +1,33 @@
+package inject_test
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_MapSupplier(t *testing.T) {
+	injector := inject.New()
+	calls := 0
+	inject.MapSupplier(injector, func() *regexp.Regexp {
+		calls++
+		return regexp.MustCompile(`^\d+$`)
+	})
+	inject.MapSupplier(injector, func() interface{ MatchString(string) bool } {
+		return regexp.MustCompile(`^[a-z]+$`)
+	}, inject.Named("words"))
+	expect(t, calls, 0)
+
+	var digits struct {
+		Re    *regexp.Regexp                        `inject`
+		Words interface{ MatchString(string) bool } `inject:"name=words"`
+	}
+	expect(t, injector.Apply(&digits), nil)
+	expect(t, digits.Re.MatchString("42"), true)
+	expect(t, digits.Words.MatchString("go"), true)
+	expect(t, injector.Get(reflect.TypeOf(digits.Re)).Interface(), digits.Re)
+	expect(t, calls, 1)
+	expect(t, injector.Validate(&digits), nil)
+}
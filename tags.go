@@ -0,0 +1,329 @@
+package inject
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TagName returns an Option making the injector inject the struct fields
+// tagged with name, e.g. `di:"group"`, instead of inject, which avoids
+// clashing with another library reading the inject tag.
+func TagName(name string) Option {
+	return func(i *injector) {
+		i.tag = name
+	}
+}
+
+// tagKey returns the key of the struct tags the injector injects.
+func (i *injector) tagKey() string {
+	if i.tag == "" {
+		return "inject"
+	}
+	return i.tag
+}
+
+// tagged reports whether the struct field f is to be injected: its tag is
+// either the bare `inject` or has an inject key, such as in
+// `inject:"" env:"PORT"`.
+func (i *injector) tagged(f reflect.StructField) bool {
+	if string(f.Tag) == i.tagKey() {
+		return true
+	}
+	_, ok := f.Tag.Lookup(i.tagKey())
+	return ok
+}
+
+// injectable returns the tagged fields of the struct type t, including
+// the fields promoted from embedded structs that are not tagged themselves,
+// e.g. the Logger and DB fields of an embedded BaseController, in the order
+// of reflect.VisibleFields. The fields of a tagged embedded struct are left
+// out, since the embedded struct is injected as a whole.
+// The fields of a type and their parsed tags are computed once per tag key
+// and presets and then cached.
+func (i *injector) injectable(t reflect.Type) []injectField {
+	key := fieldsKey{i.tagKey(), i.presets, t}
+	if fields, ok := fieldsCache.Load(key); ok {
+		return fields.([]injectField)
+	}
+	fields := i.visibleTagged(t)
+	fieldsCache.Store(key, fields)
+	return fields
+}
+
+// injectField is a field returned by injectable along with its parsed
+// tags.
+type injectField struct {
+	reflect.StructField
+	tag fieldTag
+}
+
+type fieldsKey struct {
+	tag     string
+	presets *tagPresets
+	typ     reflect.Type
+}
+
+// fieldTag holds the options of the inject tag of a field, with its
+// presets expanded, and its env and default tags.
+type fieldTag struct {
+	// unknown is the first preset used by the tag that is not defined.
+	unknown string
+	// group and named are set by the bare group and named options.
+	group, named bool
+	// groupName and name are the values of the group= and name= options.
+	groupName, name       string
+	hasGroupName, hasName bool
+	env                   string
+	def                   string
+	hasDefault            bool
+}
+
+// fieldsCache holds the fields returned by injectable.
+var fieldsCache sync.Map
+
+// visibleTagged computes the fields returned by injectable.
+func (i *injector) visibleTagged(t reflect.Type) []injectField {
+	var fields []injectField
+	var wholes [][]int
+	for _, f := range reflect.VisibleFields(t) {
+		if within(f.Index, wholes) || !i.tagged(f) {
+			continue
+		}
+		fields = append(fields, injectField{f, i.parseTag(f)})
+		if f.Anonymous {
+			wholes = append(wholes, f.Index)
+		}
+	}
+	return fields
+}
+
+// within reports whether the field at index is a field of one of the
+// embedded fields at prefixes.
+func within(index []int, prefixes [][]int) bool {
+	for _, p := range prefixes {
+		if len(p) < len(index) && reflect.DeepEqual(p, index[:len(p)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// settable returns the field of the struct v at index when it can be set,
+// which is not the case of unexported fields and of fields promoted
+// through a nil embedded pointer.
+func settable(v reflect.Value, index []int) (reflect.Value, bool) {
+	f, err := v.FieldByIndexErr(index)
+	if err != nil || !f.CanSet() {
+		return reflect.Value{}, false
+	}
+	return f, true
+}
+
+// TagPreset returns an Option defining the named preset of the options of
+// the inject tag: a field tagged `inject:"preset=grpcdeps"` is injected as
+// if its tag held options, e.g. TagPreset("grpcdeps", "group=grpc"), so
+// that a large code base changes the injection of many fields in one
+// place. Options of the tag itself win over those of its presets.
+// Children inherit the presets.
+func TagPreset(name, options string) Option {
+	return func(i *injector) {
+		// copied since the presets are shared with the children
+		presets := &tagPresets{options: make(map[string]string)}
+		if i.presets != nil {
+			for k, v := range i.presets.options {
+				presets.options[k] = v
+			}
+		}
+		presets.options[name] = options
+		i.presets = presets
+	}
+}
+
+// tagPresets holds the presets defined with TagPreset, never modified
+// once the injector is configured, so that the fields parsed with them
+// are cached per presets, see injectable.
+type tagPresets struct {
+	options map[string]string
+}
+
+// expand returns the options of tag, with the presets it uses expanded
+// after its own options, and the first of those presets that is not
+// defined.
+func (i *injector) expand(tag string) (options, unknown string) {
+	var own, presets []string
+	for _, o := range strings.Split(tag, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(o), "=")
+		if !ok || k != "preset" {
+			own = append(own, o)
+			continue
+		}
+		options, defined := "", false
+		if i.presets != nil {
+			options, defined = i.presets.options[v]
+		}
+		if !defined && unknown == "" {
+			unknown = v
+		}
+		presets = append(presets, options)
+	}
+	return strings.Join(append(own, presets...), ","), unknown
+}
+
+// parseTag parses the tags of the struct field f. The options of the inject
+// tag come before the options of its presets, so that the first of the
+// options with the same key wins.
+func (i *injector) parseTag(f reflect.StructField) fieldTag {
+	var tag fieldTag
+	options := f.Tag.Get(i.tagKey())
+	if strings.Contains(options, "preset=") {
+		options, tag.unknown = i.expand(options)
+	}
+	for _, o := range strings.Split(options, ",") {
+		o = strings.TrimSpace(o)
+		k, v, ok := strings.Cut(o, "=")
+		switch {
+		case o == "group":
+			tag.group = true
+		case o == "named":
+			tag.named = true
+		case ok && k == "group" && !tag.hasGroupName:
+			tag.groupName, tag.hasGroupName = v, true
+		case ok && k == "name" && !tag.hasName:
+			tag.name, tag.hasName = v, true
+		}
+	}
+	tag.env = f.Tag.Get("env")
+	tag.def, tag.hasDefault = f.Tag.Lookup("default")
+	return tag
+}
+
+// field resolves the value of the tagged struct field f as part of c. A
+// slice field tagged `inject:"group"` gathers every bound value assignable
+// to its element type, one tagged `inject:"group=handlers"` the members of
+// the handlers group, see Group, a map[string]T field tagged `inject:"named"` every
+// named binding assignable to T, and a field tagged `inject:"name=json"`
+// receives the binding of its type named json. A field with an env tag, e.g.
+// `inject:"" env:"PORT"`, is parsed from the environment variable it names
+// when the variable is set. Otherwise the field is resolved with resolve,
+// and a field with a default tag is parsed from the tag when its type
+// cannot be resolved.
+func (i *injector) field(f *injectField, c *call, resolve func(reflect.Type) (reflect.Value, error)) (reflect.Value, error) {
+	tag := &f.tag
+	if tag.unknown != "" {
+		return reflect.Value{}, fmt.Errorf("Unknown tag preset %q", tag.unknown)
+	}
+	if f.Type.Kind() == reflect.Slice && tag.group {
+		defer c.find(FromGroup, i)
+		return i.group(f.Type, c)
+	}
+	if tag.hasGroupName && f.Type.Kind() == reflect.Slice {
+		defer c.find(FromGroup, i)
+		return i.groupSlice(tag.groupName, f.Type, c)
+	}
+	if f.Type.Kind() == reflect.Map && f.Type.Key().Kind() == reflect.String && tag.named {
+		defer c.find(FromNamed, i)
+		return i.namedMap(f.Type, c)
+	}
+	if tag.hasName {
+		resolve = func(t reflect.Type) (reflect.Value, error) {
+			return i.lookupNamed(t, tag.name, c)
+		}
+	}
+	if tag.env != "" {
+		if s, ok := os.LookupEnv(tag.env); ok {
+			c.find(FromEnv, i)
+			return parseText(s, f.Type)
+		}
+	}
+	val, err := resolve(f.Type)
+	if tag.hasDefault && missing(err) {
+		c.find(FromDefault, i)
+		return parseText(tag.def, f.Type)
+	}
+	return val, err
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	urlType             = reflect.TypeOf(url.URL{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// parseText converts the text s of an env or default tag into a value of
+// type t. Besides strings, booleans and numbers, s may hold a
+// time.Duration, a url.URL, a value of a type whose pointer implements
+// encoding.TextUnmarshaler, such as net.IP or time.Time, or a comma
+// separated list of any of those for slices. A pointer type receives a
+// pointer to the parsed value.
+func parseText(s string, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	if err := setText(v, s); err != nil {
+		return reflect.Value{}, fmt.Errorf("Cannot parse %q as %v: %w", s, t, err)
+	}
+	return v, nil
+}
+
+// setText parses s into v.
+func setText(v reflect.Value, s string) error {
+	t := v.Type()
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+	switch t {
+	case durationType:
+		d, err := time.ParseDuration(s)
+		v.SetInt(int64(d))
+		return err
+	case urlType:
+		u, err := url.Parse(s)
+		if err == nil {
+			v.Set(reflect.ValueOf(*u))
+		}
+		return err
+	}
+
+	switch k := t.Kind(); {
+	case k == reflect.Ptr:
+		v.Set(reflect.New(t.Elem()))
+		return setText(v.Elem(), s)
+	case k == reflect.String:
+		v.SetString(s)
+	case k == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		v.SetBool(b)
+		return err
+	case k >= reflect.Int && k <= reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, t.Bits())
+		v.SetInt(n)
+		return err
+	case k >= reflect.Uint && k <= reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 0, t.Bits())
+		v.SetUint(n)
+		return err
+	case k == reflect.Float32 || k == reflect.Float64:
+		f, err := strconv.ParseFloat(s, t.Bits())
+		v.SetFloat(f)
+		return err
+	case k == reflect.Slice:
+		if s == "" {
+			return nil
+		}
+		parts := strings.Split(s, ",")
+		v.Set(reflect.MakeSlice(t, len(parts), len(parts)))
+		for n, part := range parts {
+			if err := setText(v.Index(n), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported type")
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package inject_test
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codegangsta/inject"
+)
+
+type ServerConfig struct {
+	Port    int           `inject:"" env:"INJECT_TEST_PORT" default:"8080"`
+	Timeout time.Duration `inject:"" default:"1m30s"`
+	Hosts   []string      `inject:"" env:"INJECT_TEST_HOSTS"`
+	IP      net.IP        `inject:"" default:"10.0.0.1"`
+	Upload  *url.URL      `inject:"" default:"https://example.com/upload"`
+	Started time.Time     `inject:"" default:"2024-01-02T03:04:05Z"`
+	Name    string        `inject`
+}
+
+func Test_InjectorEnvAndDefaultTags(t *testing.T) {
+	t.Setenv("INJECT_TEST_HOSTS", "a, b,c")
+	injector := inject.New()
+	injector.Map("server")
+
+	var cfg ServerConfig
+	expect(t, injector.Apply(&cfg), nil)
+	expect(t, cfg.Port, 8080)
+	expect(t, cfg.Timeout, 90*time.Second)
+	expect(t, len(cfg.Hosts), 3)
+	expect(t, cfg.Hosts[1], "b")
+	expect(t, cfg.IP.String(), "10.0.0.1")
+	expect(t, cfg.Upload.Host, "example.com")
+	expect(t, cfg.Started.Year(), 2024)
+	expect(t, cfg.Name, "server")
+	expect(t, injector.Validate(&cfg), nil)
+
+	t.Setenv("INJECT_TEST_PORT", "9090")
+	injector.Map(7070)
+	expect(t, injector.Apply(&cfg), nil)
+	expect(t, cfg.Port, 9090)
+}
+
+func Test_InjectorDefaultTagBinding(t *testing.T) {
+	injector := inject.New()
+	injector.Map(7070).Map([]string{"x"}).Map("server")
+
+	var cfg ServerConfig
+	expect(t, injector.Apply(&cfg), nil)
+	expect(t, cfg.Port, 7070)
+
+	bad := struct {
+		N uint8 `inject:"" default:"300"`
+	}{}
+	refute(t, injector.Apply(&bad), nil)
+}
+
+type BaseController struct {
+	Name string `inject`
+}
+
+type baseRepository struct {
+	Rate float64 `inject`
+}
+
+type UsersController struct {
+	BaseController
+	*baseRepository
+	Level int `inject`
+}
+
+type Tagged struct {
+	BaseController `inject`
+}
+
+func Test_InjectorApplyEmbedded(t *testing.T) {
+	injector := inject.New()
+	injector.Map("users").Map(2).Map(0.5)
+	injector.Map(BaseController{Name: "whole"})
+
+	var c UsersController
+	expect(t, injector.Apply(&c), nil)
+	expect(t, c.Name, "users")
+	expect(t, c.Level, 2)
+	expect(t, c.baseRepository == nil, true)
+
+	c = UsersController{baseRepository: &baseRepository{}}
+	expect(t, injector.Apply(&c), nil)
+	expect(t, c.Rate, 0.5)
+
+	explanations, err := injector.ExplainApply(&c)
+	expect(t, err, nil)
+	expect(t, len(explanations), 3)
+	expect(t, explanations[0].Name, "Name")
+
+	var tagged Tagged
+	expect(t, injector.Apply(&tagged), nil)
+	expect(t, tagged.Name, "whole")
+
+	var constructed *UsersController
+	expect(t, injector.Construct(&constructed), nil)
+	expect(t, constructed.Name, "users")
+}
+
+type presetDeps struct {
+	Handlers []string `inject:"preset=grpcdeps"`
+	Codec    string   `inject:"preset=codec"`
+	Plain    string   `inject:"preset=codec,name=plain"`
+}
+
+func Test_InjectorTagPreset(t *testing.T) {
+	injector := inject.New(inject.TagPreset("grpcdeps", "group=grpc"), inject.TagPreset("codec", "name=json"))
+	injector.MapWith("users", inject.Group("grpc")).MapWith("orders", inject.Group("grpc"))
+	injector.MapWith("json codec", inject.Named("json")).MapWith("plain codec", inject.Named("plain"))
+
+	var deps presetDeps
+	expect(t, injector.Apply(&deps), nil)
+	expect(t, strings.Join(deps.Handlers, ","), "users,orders")
+	expect(t, deps.Codec, "json codec")
+	expect(t, deps.Plain, "plain codec")
+	expect(t, injector.Child().Apply(&presetDeps{}), nil)
+	expect(t, injector.Validate(&deps), nil)
+
+	unknown := inject.New(inject.TagPreset("codec", "name=json"))
+	err := unknown.Apply(&presetDeps{})
+	expect(t, err.Error(), `Cannot inject Handlers []string of inject_test.presetDeps: Unknown tag preset "grpcdeps"`)
+	// the named bindings the presets select are missing as well
+	err = unknown.Validate(&presetDeps{})
+	lines := strings.Split(err.Error(), "\n")
+	expect(t, len(lines), 3)
+	expect(t, lines[0], `Field Handlers of inject_test.presetDeps uses the unknown tag preset "grpcdeps"`)
+}
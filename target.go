@@ -0,0 +1,157 @@
+package inject
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Target describes the consumer a dependency is injected into: an argument
+// of a function or a field of a struct. A provider taking a Target
+// argument receives the consumer of the value it builds, e.g. to tag a
+// logger with the component requesting it:
+//
+//	inj.Provide(func(t inject.Target) *slog.Logger {
+//		return slog.Default().With("component", t.Consumer)
+//	})
+//
+// Unless it is transient, such a provider is memoized by consumer: every
+// consumer gets its own value, which it keeps getting on every resolution.
+// The Shared and Weak options do not apply to it. A Target resolved
+// outside of a provider, or for a value requested with Get or Populate, is
+// the zero Target.
+type Target struct {
+	// Consumer is the name of the function or struct type being injected.
+	Consumer string
+	// Package is the import path of the package declaring the function or
+	// struct type, if known.
+	Package string
+	// Owner is the type of the function or struct being injected.
+	Owner reflect.Type
+	// Name is "#n" for the n-th argument of a function, the field name for
+	// a struct field.
+	Name string
+	// Type is the type of the argument or field.
+	Type reflect.Type
+}
+
+func (t Target) String() string {
+	if t.Owner == nil {
+		return "<none>"
+	}
+	if t.Owner.Kind() == reflect.Func {
+		return t.Consumer + t.Name
+	}
+	return t.Consumer + "." + t.Name
+}
+
+var targetType = reflect.TypeOf(Target{})
+
+// consumer is the argument or field a call is resolving a dependency for,
+// from which the Target is only built when it is requested.
+type consumer struct {
+	// owner is the type of the function whose argument index is being
+	// resolved, or of the struct whose field is.
+	owner reflect.Type
+	fn    interface{}
+	index int
+	field string
+}
+
+// target returns the Target of the dependency c is resolving.
+func (c *call) target() Target {
+	k := c.consumer
+	switch {
+	case k.fn != nil:
+		return funcTarget(reflect.ValueOf(k.fn), k.index)
+	case k.owner != nil:
+		f, _ := k.owner.FieldByName(k.field)
+		return fieldTarget(k.owner, f)
+	}
+	return Target{}
+}
+
+// funcTarget returns the Target of the n-th argument of the function fn.
+func funcTarget(fn reflect.Value, n int) Target {
+	t := fn.Type()
+	return Target{funcName(fn), funcPackage(fn), t, fmt.Sprintf("#%d", n), t.In(n)}
+}
+
+// fieldTarget returns the Target of the field f of the struct type owner.
+func fieldTarget(owner reflect.Type, f reflect.StructField) Target {
+	return Target{owner.String(), owner.PkgPath(), owner, f.Name, f.Type}
+}
+
+// funcPackage returns the import path of the package declaring fn, or an
+// empty string if it is unknown.
+func funcPackage(fn reflect.Value) string {
+	f := runtime.FuncForPC(fn.Pointer())
+	if f == nil {
+		return ""
+	}
+	return symbolPackage(f.Name())
+}
+
+// symbolPackage returns the import path of the package of the function
+// named name by the runtime, e.g. gopkg.in/yaml%2ev3.(*Decoder).Decode.
+// The path ends at the first dot after its last slash: the runtime
+// escapes the dots of the last element of the path, which are unescaped,
+// and elides the type arguments of generic functions, which may hold
+// slashes, as [...].
+func symbolPackage(name string) string {
+	if bracket := strings.IndexByte(name, '['); bracket >= 0 {
+		name = name[:bracket]
+	}
+	slash := strings.LastIndexByte(name, '/')
+	dot := strings.IndexByte(name[slash+1:], '.')
+	if dot < 0 {
+		return ""
+	}
+	path, err := url.PathUnescape(name[:slash+1+dot])
+	if err != nil {
+		return name[:slash+1+dot]
+	}
+	return path
+}
+
+// takes reports whether the function type ft has an argument of type t.
+func takes(ft reflect.Type, t reflect.Type) bool {
+	for n := 0; n < ft.NumIn(); n++ {
+		if ft.In(n) == t {
+			return true
+		}
+	}
+	return false
+}
+
+// provideTargeted returns the value of the memoized provider of e for the
+// consumer c is resolving t for, building it for a new consumer. The
+// provider lock is held.
+func (i *injector) provideTargeted(t reflect.Type, e *binding, c *call) (reflect.Value, error) {
+	p := e.provider
+	target := c.target()
+	if val, ok := p.consumers[target]; ok {
+		return val, nil
+	}
+	if err := c.done(t); err != nil {
+		return reflect.Value{}, err
+	}
+	val, fresh, err := i.build(t, e, c.at(i))
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if fresh {
+		if err := created(t, e, val); err != nil {
+			return reflect.Value{}, err
+		}
+		i.track(val.Interface())
+		i.destroyed(e, val)
+	}
+	if p.consumers == nil {
+		p.consumers = make(map[Target]reflect.Value)
+	}
+	p.consumers[target] = val
+	return val, nil
+}
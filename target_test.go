@@ -0,0 +1,98 @@
+package inject_test
+
+import (
+	"bytes"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type namedLogger struct {
+	name string
+}
+
+type targetedService struct {
+	Log *namedLogger `inject`
+}
+
+func Test_InjectorTarget(t *testing.T) {
+	injector := inject.New()
+	injector.Provide(func(target inject.Target) *namedLogger {
+		return &namedLogger{target.String()}
+	}, inject.Transient())
+
+	var s targetedService
+	expect(t, injector.Apply(&s), nil)
+	expect(t, s.Log.name, "inject_test.targetedService.Log")
+
+	_, err := injector.Invoke(func(l *namedLogger) {
+		expect(t, l.name[len(l.name)-2:], "#0")
+	})
+	expect(t, err, nil)
+
+	var target inject.Target
+	expect(t, injector.Populate(&target), nil)
+	expect(t, target, inject.Target{})
+	expect(t, target.String(), "<none>")
+}
+
+func Test_InjectorTargetConstruct(t *testing.T) {
+	injector := inject.New()
+	var got inject.Target
+	injector.Provide(func(target inject.Target) *namedLogger {
+		got = target
+		return &namedLogger{target.Consumer}
+	})
+
+	var s targetedService
+	expect(t, injector.Construct(&s), nil)
+	expect(t, got.Owner, reflect.TypeOf(s))
+	expect(t, got.Name, "Log")
+	expect(t, got.Type, reflect.TypeOf(&namedLogger{}))
+	expect(t, injector.Validate(&s), nil)
+}
+
+type auditService struct {
+	Log *slog.Logger `inject`
+}
+
+type billingService struct {
+	Log *slog.Logger `inject`
+}
+
+func Test_InjectorTargetedProvider(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	calls := 0
+	injector := inject.New()
+	injector.Provide(func(target inject.Target) *slog.Logger {
+		calls++
+		return slog.New(handler).With("component", target.Consumer)
+	})
+
+	var audit, audit2 auditService
+	var billing billingService
+	expect(t, injector.Apply(&audit), nil)
+	expect(t, injector.Apply(&billing), nil)
+	expect(t, injector.Apply(&audit2), nil)
+	expect(t, calls, 2)
+	expect(t, audit.Log, audit2.Log)
+
+	audit.Log.Info("checked")
+	billing.Log.Info("charged")
+	expect(t, buf.String(), "level=INFO msg=checked component=inject_test.auditService\n"+
+		"level=INFO msg=charged component=inject_test.billingService\n")
+
+	expect(t, injector.Invalidate(reflect.TypeOf(audit.Log)), nil)
+	expect(t, injector.Apply(&audit2), nil)
+	expect(t, calls, 3)
+}
@@ -0,0 +1,33 @@
+package inject
+
+import (
+	"fmt"
+	"html/template"
+	"reflect"
+)
+
+// FuncMap builds a template.FuncMap from funcs, whose leading arguments are
+// resolved from inj once, when FuncMap is called. The arguments left after
+// the longest prefix inj can resolve are the ones passed by the template,
+// so they must come last and should not have mapped types. For example
+//
+//	func(users *UserService, id int) (string, error)
+//
+// is called as {{user 42}} once *UserService is mapped.
+// Returns an error if a value is not a function or if resolving one of the
+// arguments fails.
+func FuncMap(inj Injector, funcs map[string]interface{}) (template.FuncMap, error) {
+	fm := make(template.FuncMap, len(funcs))
+	for name, f := range funcs {
+		if reflect.TypeOf(f).Kind() != reflect.Func {
+			return nil, fmt.Errorf("Template function %q is not a function", name)
+		}
+
+		fn, err := bindLeading(inj, f)
+		if err != nil {
+			return nil, fmt.Errorf("Template function %q: %w", name, err)
+		}
+		fm[name] = fn.Interface()
+	}
+	return fm, nil
+}
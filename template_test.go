@@ -0,0 +1,29 @@
+package inject_test
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_FuncMap(t *testing.T) {
+	injector := inject.New()
+	injector.Map(&Config{Name: "Hello"})
+
+	fm, err := inject.FuncMap(injector, map[string]interface{}{
+		"greet": func(c *Config, name string) string { return c.Name + " " + name },
+		"join":  func(c *Config, parts ...string) string { return c.Name + strings.Join(parts, "") },
+	})
+	expect(t, err, nil)
+
+	tmpl := template.Must(template.New("t").Funcs(fm).Parse(`{{greet "world"}} {{join "a" "b"}}`))
+	var out bytes.Buffer
+	expect(t, tmpl.Execute(&out, nil), nil)
+	expect(t, out.String(), "Hello world Helloab")
+
+	_, err = inject.FuncMap(injector, map[string]interface{}{"bad": 42})
+	refute(t, err, nil)
+}
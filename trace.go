@@ -0,0 +1,71 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TraceKind tells what a step of a trace did.
+type TraceKind string
+
+// Kinds of the steps recorded by InvokeTraced.
+const (
+	// TraceLocal is a type found in the Type map of an injector.
+	TraceLocal TraceKind = "local"
+	// TraceParent is a type asked to a parent.
+	TraceParent TraceKind = "parent"
+	// TraceAdapt is a type resolved from another bound type, such as a
+	// directional channel, a function of the same signature, an
+	// implementation of an interface or a conversion.
+	TraceAdapt TraceKind = "adapt"
+	// TraceBuiltin is a type resolved without a binding: the requesting
+	// injector, a context or a fake.
+	TraceBuiltin TraceKind = "builtin"
+	// TraceMiss is a type an injector cannot resolve.
+	TraceMiss TraceKind = "miss"
+)
+
+// TraceStep is a step of the resolution of a dependency.
+type TraceStep struct {
+	Kind TraceKind
+	Type reflect.Type
+	// Scope is the scope of the injector performing the step.
+	Scope Scope
+	// Detail describes the step, e.g. whether a local hit is a mapped
+	// value or a provider.
+	Detail string
+	// Depth is the number of providers being built when the step is
+	// performed, 0 for the arguments of the invoked function itself.
+	Depth int
+}
+
+func (s TraceStep) String() string {
+	str := fmt.Sprintf("%s%s %v", strings.Repeat("  ", s.Depth), s.Kind, s.Type)
+	if s.Scope != "" {
+		str += " in " + string(s.Scope)
+	}
+	if s.Detail != "" {
+		str += ": " + s.Detail
+	}
+	return str
+}
+
+// InvokeTraced calls f like Invoke and returns, along with its results,
+// the steps of every lookup performed to resolve its arguments and the
+// arguments of the providers built along the way, in order. It helps
+// understanding a surprising resolution or a slow one without a debugger.
+func (i *injector) InvokeTraced(f interface{}) ([]reflect.Value, []TraceStep, error) {
+	var steps []TraceStep
+	c := newCall(i)
+	c.trace = &steps
+	out, err := i.invoke(f, c, nil)
+	return out, steps, err
+}
+
+// step records a step of the lookup of t by i when c is traced.
+func (c *call) step(kind TraceKind, i *injector, t reflect.Type, detail string) {
+	if c.trace != nil {
+		*c.trace = append(*c.trace, TraceStep{kind, t, i.Scope(), detail, len(c.providing)})
+	}
+}
@@ -0,0 +1,40 @@
+package inject_test
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorInvokeTraced(t *testing.T) {
+	parent := inject.New(inject.Implementations())
+	parent.SetScope(inject.Singleton)
+	parent.Map(strings.NewReader("r"))
+	injector := parent.Child()
+	injector.SetScope(inject.Request)
+	injector.Provide(func(r io.Reader) int { return 1 })
+
+	out, steps, err := injector.InvokeTraced(func(n int) int { return n })
+	expect(t, err, nil)
+	expect(t, out[0].Int(), int64(1))
+
+	var lines []string
+	for _, s := range steps {
+		lines = append(lines, s.String())
+	}
+	trace := strings.Join(lines, "\n")
+	expect(t, steps[0].Kind, inject.TraceLocal)
+	expect(t, steps[0].Detail, "provider")
+	expect(t, steps[0].Type, reflect.TypeOf(0))
+	expect(t, strings.Contains(trace, "  parent io.Reader in request: to scope singleton"), true)
+	expect(t, strings.Contains(trace, "  adapt io.Reader in singleton: from *strings.Reader"), true)
+	expect(t, steps[len(steps)-1].Kind, inject.TraceLocal)
+	expect(t, steps[len(steps)-1].Depth, 1)
+
+	_, steps, err = injector.InvokeTraced(func(float64) {})
+	refute(t, err, nil)
+	expect(t, steps[len(steps)-1].Kind, inject.TraceMiss)
+}
@@ -0,0 +1,122 @@
+package inject
+
+import (
+	"math/bits"
+	"reflect"
+)
+
+// typeTrie is a persistent hash trie holding the binding of every bound
+// type. with returns a modified copy sharing every node but those on the
+// path to the type bound, so that publishing a snapshot with one more
+// binding copies a few small nodes instead of every binding of the
+// injector.
+type typeTrie struct {
+	root *trieNode
+	size int
+}
+
+// trieNode is a level of a typeTrie. It holds a slot for every bit set in
+// bitmap, in the order of the bits, either a binding or the node of the
+// next level.
+type trieNode struct {
+	bitmap uint32
+	slots  []trieSlot
+}
+
+type trieSlot struct {
+	typ  reflect.Type
+	e    *binding
+	next *trieNode
+}
+
+// trieBits is the number of bits of the hash of a type consumed by every
+// level of a typeTrie.
+const trieBits = 5
+
+// typeHash returns the hash of t, derived from the address of its
+// descriptor, which never moves. Both steps of the mix are invertible, so
+// distinct types have distinct hashes and always part at some level.
+func typeHash(t reflect.Type) uint64 {
+	h := uint64(reflect.ValueOf(t).Pointer()) * 0x9e3779b97f4a7c15
+	return h ^ h>>32
+}
+
+// get returns the binding of t.
+func (tr typeTrie) get(t reflect.Type) (*binding, bool) {
+	h := typeHash(t)
+	for n := tr.root; n != nil; h >>= trieBits {
+		bit := uint32(1) << (h & 31)
+		if n.bitmap&bit == 0 {
+			return nil, false
+		}
+		s := &n.slots[bits.OnesCount32(n.bitmap&(bit-1))]
+		if s.next == nil {
+			if s.typ != t {
+				return nil, false
+			}
+			return s.e, true
+		}
+		n = s.next
+	}
+	return nil, false
+}
+
+// with returns a copy of the trie holding e as the binding of t.
+func (tr typeTrie) with(t reflect.Type, e *binding) typeTrie {
+	root, added := tr.root.with(typeHash(t), 0, t, e)
+	if added {
+		tr.size++
+	}
+	return typeTrie{root, tr.size}
+}
+
+// with returns a copy of n, which may be nil, holding e as the binding of t,
+// whose hash is h, and whether t was not bound yet. shift is the number of
+// bits of h consumed by the levels above n.
+func (n *trieNode) with(h uint64, shift uint, t reflect.Type, e *binding) (*trieNode, bool) {
+	bit := uint32(1) << (h >> shift & 31)
+	if n == nil {
+		return &trieNode{bit, []trieSlot{{typ: t, e: e}}}, true
+	}
+	k := bits.OnesCount32(n.bitmap & (bit - 1))
+	if n.bitmap&bit == 0 {
+		slots := make([]trieSlot, len(n.slots)+1)
+		copy(slots, n.slots[:k])
+		slots[k] = trieSlot{typ: t, e: e}
+		copy(slots[k+1:], n.slots[k:])
+		return &trieNode{n.bitmap | bit, slots}, true
+	}
+
+	slots := append([]trieSlot(nil), n.slots...)
+	s, added := &slots[k], false
+	switch {
+	case s.next != nil:
+		s.next, added = s.next.with(h, shift+trieBits, t, e)
+	case s.typ == t:
+		s.e = e
+	default:
+		// both types move down to the level where their hashes part
+		next, _ := (*trieNode)(nil).with(typeHash(s.typ), shift+trieBits, s.typ, s.e)
+		next, added = next.with(h, shift+trieBits, t, e)
+		*s = trieSlot{next: next}
+	}
+	return &trieNode{n.bitmap, slots}, added
+}
+
+// each calls fn with every type of the trie and its binding.
+func (tr typeTrie) each(fn func(reflect.Type, *binding)) {
+	tr.root.each(fn)
+}
+
+func (n *trieNode) each(fn func(reflect.Type, *binding)) {
+	if n == nil {
+		return
+	}
+	for _, s := range n.slots {
+		if s.next != nil {
+			s.next.each(fn)
+		} else {
+			fn(s.typ, s.e)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Func1 resolves the argument of f from inj once and returns a closure
+// calling f with it, so that a function invoked on a hot path pays for
+// the resolution only once and then runs without any reflect.Call. The
+// argument is resolved when Func1 is called: later changes of the bindings
+// of inj are not seen by the closure.
+// Returns an error if the argument cannot be resolved.
+func Func1[A any](inj Injector, f func(A)) (func(), error) {
+	a, err := resolveArg[A](inj, f, 0)
+	if err != nil {
+		return nil, err
+	}
+	return func() { f(a) }, nil
+}
+
+// Func2 works like Func1 for a function of two arguments.
+func Func2[A, B any](inj Injector, f func(A, B)) (func(), error) {
+	a, err := resolveArg[A](inj, f, 0)
+	if err != nil {
+		return nil, err
+	}
+	b, err := resolveArg[B](inj, f, 1)
+	if err != nil {
+		return nil, err
+	}
+	return func() { f(a, b) }, nil
+}
+
+// Func3 works like Func1 for a function of three arguments.
+func Func3[A, B, C any](inj Injector, f func(A, B, C)) (func(), error) {
+	a, err := resolveArg[A](inj, f, 0)
+	if err != nil {
+		return nil, err
+	}
+	b, err := resolveArg[B](inj, f, 1)
+	if err != nil {
+		return nil, err
+	}
+	c, err := resolveArg[C](inj, f, 2)
+	if err != nil {
+		return nil, err
+	}
+	return func() { f(a, b, c) }, nil
+}
+
+// ProvideFn0 registers f as the provider of T in inj like Provide, with a
+// signature checked by the compiler rather than when T is first resolved.
+// Use the variants for providers taking one to three dependencies.
+func ProvideFn0[T any](inj TypeMapper, f func() (T, error), opts ...BindOption) TypeMapper {
+	return inj.Provide(f, opts...)
+}
+
+// ProvideFn1 works like ProvideFn0 for a provider of one dependency.
+func ProvideFn1[T, A any](inj TypeMapper, f func(A) (T, error), opts ...BindOption) TypeMapper {
+	return inj.Provide(f, opts...)
+}
+
+// ProvideFn2 works like ProvideFn0 for a provider of two dependencies.
+func ProvideFn2[T, A, B any](inj TypeMapper, f func(A, B) (T, error), opts ...BindOption) TypeMapper {
+	return inj.Provide(f, opts...)
+}
+
+// ProvideFn3 works like ProvideFn0 for a provider of three dependencies.
+func ProvideFn3[T, A, B, C any](inj TypeMapper, f func(A, B, C) (T, error), opts ...BindOption) TypeMapper {
+	return inj.Provide(f, opts...)
+}
+
+// resolveArg returns the value inj resolves for the argument n of f, of
+// type T.
+func resolveArg[T any](inj Injector, f interface{}, n int) (T, error) {
+	var v T
+	if err := inj.Populate(&v); err != nil {
+		// reported for the argument of f rather than for Populate
+		if ie, ok := err.(*InjectionError); ok {
+			err = ie.Err
+		}
+		t := reflect.TypeOf(&v).Elem()
+		return v, &InjectionError{funcName(reflect.ValueOf(f)), fmt.Sprintf("#%d", n), t, err}
+	}
+	return v, nil
+}
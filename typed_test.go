@@ -0,0 +1,70 @@
+package inject_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_Func1(t *testing.T) {
+	injector := inject.New()
+	injector.Map("dep")
+	var got string
+	f, err := inject.Func1(injector, func(s string) { got = s })
+	expect(t, err, nil)
+	injector.Map("changed")
+	f()
+	expect(t, got, "dep")
+}
+
+func Test_Func3(t *testing.T) {
+	injector := inject.New()
+	injector.Map("dep").Map(2)
+	injector.MapTo(errors.New("c"), (*error)(nil))
+	var got string
+	f, err := inject.Func3(injector, func(s string, n int, c error) {
+		got = fmt.Sprintf("%s %d %v", s, n, c)
+	})
+	expect(t, err, nil)
+	f()
+	expect(t, got, "dep 2 c")
+
+	_, err = inject.Func2(injector, func(string, float64) {})
+	var ie *inject.InjectionError
+	expect(t, errors.As(err, &ie), true)
+	expect(t, ie.Name, "#1")
+	expect(t, errors.Is(err, inject.ErrNotFound), true)
+}
+
+type typedStore interface {
+	Name() string
+}
+
+type typedDB struct {
+	dsn string
+}
+
+func (db *typedDB) Name() string { return db.dsn }
+
+func Test_ProvideFn(t *testing.T) {
+	injector := inject.New()
+	inject.ProvideFn0(injector, func() (string, error) { return "postgres://", nil })
+	inject.ProvideFn1(injector, func(dsn string) (typedStore, error) {
+		return &typedDB{dsn}, nil
+	}, inject.Transient())
+	boom := errors.New("boom")
+	inject.ProvideFn2(injector, func(typedStore, string) (int, error) { return 0, boom })
+
+	_, err := injector.Invoke(func(s typedStore) {
+		expect(t, s.Name(), "postgres://")
+	})
+	expect(t, err, nil)
+
+	_, err = injector.Invoke(func(int) {})
+	expect(t, errors.Is(err, boom), true)
+
+	inject.ProvideFn3(injector, func(typedStore, string, int) (float64, error) { return 1, nil })
+	expect(t, injector.Validate(), nil)
+}
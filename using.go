@@ -0,0 +1,28 @@
+package inject
+
+import "reflect"
+
+// InvokeUsing calls f like Invoke, additionally consulting extras, in
+// order, for the types neither the injector nor its parents can resolve,
+// for this call only, e.g. to combine a stable application injector with
+// the parameters of a single batch job without creating a child.
+// The extras are consulted for the arguments of f and of the transient
+// providers the call builds, but not for the dependencies of memoized
+// providers, whose values outlive the call.
+// It panics if f is not a function.
+func (inj *injector) InvokeUsing(f interface{}, extras ...Injector) ([]reflect.Value, error) {
+	c := newCall(inj)
+	c.extras = extras
+	return inj.invoke(f, c, nil)
+}
+
+// lookupExtra resolves t from the extras of c, see InvokeUsing.
+func (i *injector) lookupExtra(t reflect.Type, c *call) (reflect.Value, error) {
+	for _, x := range c.extras {
+		c.step(TraceParent, i, t, "to extra scope "+string(x.Scope()))
+		if val, err := delegate(x, t, c); !missing(err) {
+			return val, err
+		}
+	}
+	return reflect.Value{}, ErrNotFound
+}
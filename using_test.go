@@ -0,0 +1,38 @@
+package inject_test
+
+import (
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type jobID int
+
+type jobReport struct {
+	id jobID
+}
+
+func Test_InjectorInvokeUsing(t *testing.T) {
+	app := inject.New()
+	app.Map("app")
+	app.Provide(func(id jobID) *jobReport { return &jobReport{id} }, inject.Transient())
+	app.Provide(func(id jobID) float64 { return float64(id) })
+
+	job := inject.New()
+	job.Map(jobID(7)).Map("job")
+
+	_, err := app.InvokeUsing(func(s string, id jobID, r *jobReport) {
+		expect(t, s, "app")
+		expect(t, id, jobID(7))
+		expect(t, r.id, jobID(7))
+	}, job)
+	expect(t, err, nil)
+
+	// memoized providers do not see the extras of a call
+	_, err = app.InvokeUsing(func(float64) {}, job)
+	expect(t, err != nil, true)
+
+	// the extras are for a single call
+	_, err = app.Invoke(func(jobID) {})
+	expect(t, err != nil, true)
+}
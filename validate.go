@@ -0,0 +1,179 @@
+package inject
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Validate checks that every dependency of targets can be resolved without
+// calling any function or provider. Each target is either a function, whose
+// arguments are checked, or a struct or pointer to a struct, whose tagged
+// fields are checked. The arguments of the providers involved are checked
+// recursively and every provider of the injector is checked as well.
+// Validate seals the injector first, see Seal, and logs the summary of
+// StartupBanner last.
+// Bindings reached by Validate are recorded as used, see Unused.
+// Returns an error joining every problem found.
+func (inj *injector) Validate(targets ...interface{}) error {
+	var errs []error
+	if err := inj.Seal(); err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, inj.problems(targets, true)...)
+	inj.announce()
+	return errors.Join(errs...)
+}
+
+// problems returns the problems Validate reports for targets, without
+// sealing the injector. The dependencies are resolved by a dry call, see
+// lookup, recording the bindings it reaches as used if marks is set.
+func (inj *injector) problems(targets []interface{}, marks bool) []error {
+	c := newCall(inj)
+	c.dry, c.marks = true, marks
+	var errs []error
+	for _, target := range targets {
+		errs = append(errs, inj.inspect(target, c)...)
+	}
+
+	// Checking the arguments of the providers rather than the provided types
+	// keeps providers that nothing depends on reported by Unused.
+	b := inj.snapshot()
+	for _, t := range b.providedTypes() {
+		e, _ := b.entry(t)
+		fn := e.provider.fn.Interface()
+		for n := 0; n < reflect.TypeOf(fn).NumIn(); n++ {
+			if _, err := inj.argument(fn, n, c); err != nil {
+				errs = append(errs, fmt.Errorf("Provider for type %v cannot be called: %w", t, err))
+			}
+		}
+	}
+	return errs
+}
+
+// inspect returns the problems of the dependencies of target, a function
+// or a struct, resolved as part of the dry call c.
+func (inj *injector) inspect(target interface{}, c *call) []error {
+	t := reflect.TypeOf(target)
+	if t == nil {
+		return []error{fmt.Errorf("Cannot validate a nil target")}
+	}
+	var errs []error
+	if t.Kind() == reflect.Func {
+		for n := 0; n < t.NumIn(); n++ {
+			if _, err := inj.argument(target, n, c); err != nil {
+				errs = append(errs, &InjectionError{funcName(reflect.ValueOf(target)), fmt.Sprintf("#%d", n), t.In(n), err})
+			}
+		}
+		return errs
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return []error{fmt.Errorf("Cannot validate %v, expected a function or a struct", t)}
+	}
+	for _, f := range inj.injectable(t) {
+		if f.PkgPath != "" {
+			continue
+		}
+		if f.tag.unknown != "" {
+			errs = append(errs, fmt.Errorf("Field %s of %v uses the unknown tag preset %q", f.Name, t, f.tag.unknown))
+		} else if _, err := inj.fieldValue(t, &f, c); err != nil {
+			errs = append(errs, &InjectionError{t.String(), f.Name, f.Type, err})
+		}
+	}
+	return errs
+}
+
+// Unused returns the types mapped or provided in the injector that have not
+// been resolved, either by Invoke, Apply, Construct, Populate and Get or by
+// Validate, since the injector was created or ResetUsage was last called.
+// The types are sorted by name.
+func (i *injector) Unused() []reflect.Type {
+	var unused []reflect.Type
+	for _, t := range i.snapshot().types() {
+		if s, ok := i.stats.Load(t); !ok || !s.(*typeStats).used.Load() {
+			unused = append(unused, t)
+		}
+	}
+	return unused
+}
+
+// ResetUsage forgets which bindings have been resolved so far, which starts
+// a new recording window for Unused.
+func (i *injector) ResetUsage() {
+	i.stats.Range(func(_, s interface{}) bool {
+		s.(*typeStats).used.Store(false)
+		return true
+	})
+}
+
+func sortTypes(types []reflect.Type) {
+	sort.Slice(types, func(a, b int) bool {
+		return types[a].String() < types[b].String()
+	})
+}
+
+// Strictness selects the additional checks performed by ValidateStrict.
+// Levels can be combined with the | operator.
+type Strictness int
+
+const (
+	// FailOnUnused rejects bindings that have not been resolved.
+	FailOnUnused Strictness = 1 << iota
+	// FailOnShadowed rejects bindings that hide a binding of the same type
+	// in the parent chain.
+	FailOnShadowed
+	// FailOnUnusedInterfaces rejects bindings mapped to an interface type
+	// that have not been resolved.
+	FailOnUnusedInterfaces
+)
+
+// ValidateStrict runs Validate on targets and then applies the checks
+// selected by level. Returns an error joining every problem found.
+func (inj *injector) ValidateStrict(level Strictness, targets ...interface{}) error {
+	errs := []error{inj.Validate(targets...)}
+
+	for _, t := range inj.Unused() {
+		switch {
+		case level&FailOnUnused != 0:
+			errs = append(errs, fmt.Errorf("Binding for type %v is never used", t))
+		case level&FailOnUnusedInterfaces != 0 && t.Kind() == reflect.Interface:
+			errs = append(errs, fmt.Errorf("Interface binding for type %v has no consumer", t))
+		}
+	}
+
+	if level&FailOnShadowed != 0 {
+		for _, t := range inj.Shadowed() {
+			errs = append(errs, fmt.Errorf("Binding for type %v shadows a binding of the parent", t))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Shadowed returns the types mapped or provided in the injector that are
+// also mapped or provided in its parent chain, sorted by name. Such
+// bindings silently hide the ones of the parents, which commonly makes a
+// child configured by tests behave differently from production.
+func (i *injector) Shadowed() []reflect.Type {
+	inParent := make(map[reflect.Type]bool)
+	for _, p := range i.snapshot().parents {
+		if l, ok := p.inj.(typeLister); ok {
+			for _, c := range l.mappedTypes() {
+				inParent[c.typ] = p.allows(c.typ) || inParent[c.typ]
+			}
+		}
+	}
+
+	var types []reflect.Type
+	for _, t := range i.snapshot().types() {
+		if inParent[t] {
+			types = append(types, t)
+		}
+	}
+	return types
+}
@@ -0,0 +1,112 @@
+package inject_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+func Test_InjectorValidate(t *testing.T) {
+	injector := inject.New()
+	called := false
+	injector.Map("a dep")
+	injector.Provide(func(dep string) *Config {
+		called = true
+		return &Config{Name: dep}
+	})
+
+	expect(t, injector.Validate(func(*Config, string) {}, &Repository{}), nil)
+	expect(t, called, false)
+
+	err := injector.Validate(func(int) {}, Service{})
+	expect(t, errors.Is(err, inject.ErrNotFound), true)
+
+	injector.Provide(func(float64) int { return 0 })
+	expect(t, injector.Validate() == nil, false)
+
+	expect(t, injector.Validate(42) == nil, false)
+}
+
+func Test_InjectorUnused(t *testing.T) {
+	injector := inject.New()
+	injector.Map("a dep").Map(42)
+	injector.Provide(func() *Config { return &Config{} })
+
+	expect(t, len(injector.Unused()), 3)
+
+	expect(t, injector.Validate(), nil)
+	expect(t, len(injector.Unused()), 3)
+
+	injector.Validate(func(*Config) {})
+	_, err := injector.Invoke(func(string) {})
+	expect(t, err, nil)
+
+	unused := injector.Unused()
+	expect(t, len(unused), 1)
+	expect(t, unused[0], reflect.TypeOf(42))
+
+	injector.ResetUsage()
+	expect(t, len(injector.Unused()), 3)
+}
+
+func Test_InjectorValidateStrict(t *testing.T) {
+	parent := inject.New()
+	parent.Map("parent dep")
+	injector := inject.New()
+	injector.SetParent(parent)
+	injector.Map("child dep").MapTo("special", (*SpecialString)(nil))
+
+	fn := func(string) {}
+	expect(t, injector.ValidateStrict(0, fn), nil)
+	expect(t, injector.ValidateStrict(inject.FailOnShadowed, fn) == nil, false)
+	expect(t, injector.ValidateStrict(inject.FailOnUnusedInterfaces, fn) == nil, false)
+	expect(t, injector.ValidateStrict(inject.FailOnUnused, fn) == nil, false)
+
+	fn2 := func(string, SpecialString) {}
+	expect(t, injector.ValidateStrict(inject.FailOnUnused|inject.FailOnUnusedInterfaces, fn2), nil)
+}
+
+func Test_InjectorShadowed(t *testing.T) {
+	grandparent := inject.New()
+	grandparent.Map(42)
+	parent := inject.New()
+	parent.SetParent(grandparent)
+	parent.Map("parent dep")
+	injector := inject.New()
+	injector.SetParent(parent)
+	injector.Map("child dep").Map(1).Map(1.5)
+	injector.Provide(func() *Config { return nil })
+
+	expect(t, len(parent.Shadowed()), 0)
+	shadowed := injector.Shadowed()
+	expect(t, len(shadowed), 2)
+	expect(t, shadowed[0], reflect.TypeOf(0))
+	expect(t, shadowed[1], reflect.TypeOf(""))
+}
+
+type validateInt int
+
+type validateOption func(*Config)
+
+func Test_InjectorValidateFollowsLookup(t *testing.T) {
+	platform := inject.New()
+	platform.Provide(func() *Config { return &Config{} })
+
+	injector := inject.New(inject.Conversions())
+	injector.Map(validateInt(3))
+	injector.MapRef(platform, reflect.TypeOf(&Config{}))
+	injector.MapWith(validateOption(func(*Config) {}), inject.Named("timeout"))
+
+	f := func(context.Context, int, *Config, []validateOption) {}
+	_, err := injector.Invoke(f)
+	expect(t, err, nil)
+	expect(t, injector.Validate(f), nil)
+
+	cyclic := inject.New()
+	cyclic.Provide(func(string) int { return 0 })
+	cyclic.Provide(func(int) string { return "" })
+	expect(t, errors.Is(cyclic.Validate(), inject.ErrCycle), true)
+}
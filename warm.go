@@ -0,0 +1,175 @@
+package inject
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WarmOption configures a call to Warm.
+type WarmOption func(*warmup)
+
+// warmup is the state of a single call to Warm.
+type warmup struct {
+	workers  int
+	progress func(WarmEvent)
+	degraded *[]Degraded
+
+	mu    sync.Mutex
+	times map[reflect.Type]time.Duration
+	done  int
+	total int
+}
+
+// WarmEvent reports the outcome of warming one provider.
+type WarmEvent struct {
+	Type reflect.Type
+	// Duration is the time the provider function itself took to run,
+	// excluding the construction of its dependencies, or zero if the
+	// value had already been built before Warm was called.
+	Duration time.Duration
+	Err      error
+	// Done is the number of providers warmed so far, including this one,
+	// out of Total.
+	Done, Total int
+}
+
+// WarmParallel returns a WarmOption warming up to n providers at once.
+// Providers depending on each other wait for each other, so the graph must
+// be free of cycles, see Validate.
+// It panics if n is not positive.
+func WarmParallel(n int) WarmOption {
+	if n <= 0 {
+		panic("Called inject.WarmParallel with a number of workers that is not positive")
+	}
+	return func(w *warmup) {
+		w.workers = n
+	}
+}
+
+// WarmProgress returns a WarmOption calling fn once every provider is
+// warmed, which lets a service log which constructor slows its startup
+// down. Calls to fn are serialized, even when warming in parallel.
+func WarmProgress(fn func(WarmEvent)) WarmOption {
+	return func(w *warmup) {
+		w.progress = fn
+	}
+}
+
+// Optional returns a BindOption marking the binding as optional for the
+// service: a failure of its provider does not prevent a Warm call with the
+// WarmPartial option from succeeding.
+func Optional() BindOption {
+	return func(e *binding) {
+		e.optional = true
+	}
+}
+
+// Degraded reports an Optional binding whose provider failed to warm.
+type Degraded struct {
+	Type reflect.Type
+	Err  error
+}
+
+// WarmPartial returns a WarmOption continuing past the failures of
+// Optional bindings, so that a service can start with reduced
+// functionality rather than refusing to boot. The failed bindings are
+// stored in degraded, sorted by type, and Warm only fails if a binding
+// that is not optional fails, including one depending on a degraded
+// binding.
+func WarmPartial(degraded *[]Degraded) WarmOption {
+	return func(w *warmup) {
+		w.degraded = degraded
+	}
+}
+
+// Warm builds the values of every memoized provider of the injector, in
+// the order of their types, so that a service pays for its expensive
+// constructors at startup rather than on its first requests. Providers
+// accepting a context.Context receive ctx, and Warm gives up once ctx is
+// done. Transient providers and the providers of parents are not invoked,
+// and warming does not count as a use of the bindings, see Unused.
+// Returns the error of the first provider that fails. The providers left
+// are then not invoked, but still reported to WarmProgress.
+func (i *injector) Warm(ctx context.Context, opts ...WarmOption) error {
+	w := &warmup{workers: 1, times: make(map[reflect.Type]time.Duration)}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	b := i.snapshot()
+	var types []reflect.Type
+	for _, t := range b.types() {
+		if e, _ := b.entry(t); e.provider != nil && !e.provider.transient {
+			types = append(types, t)
+		}
+	}
+	w.total = len(types)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	queue := make(chan reflect.Type)
+	errs := make(chan error, len(types))
+	var wg sync.WaitGroup
+	for n := 0; n < w.workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range queue {
+				e, _ := b.entry(t)
+				if err := i.warm(ctx, t, e, w); err != nil {
+					errs <- err
+					cancel()
+				}
+			}
+		}()
+	}
+
+	for _, t := range types {
+		queue <- t
+	}
+	close(queue)
+	wg.Wait()
+	i.announce()
+	if w.degraded != nil {
+		sort.Slice(*w.degraded, func(a, b int) bool {
+			return (*w.degraded)[a].Type.String() < (*w.degraded)[b].Type.String()
+		})
+	}
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+		return nil
+	}
+}
+
+// warm builds the value of t from its binding e as part of w.
+func (i *injector) warm(ctx context.Context, t reflect.Type, e *binding, w *warmup) error {
+	c := newCall(i)
+	c.ctx = ctx
+	c.observe = w.observe
+	_, err := i.provide(t, e, c)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.done++
+	if w.progress != nil {
+		w.progress(WarmEvent{t, w.times[t], err, w.done, w.total})
+	}
+	if err != nil && e.optional && w.degraded != nil && ctx.Err() == nil {
+		*w.degraded = append(*w.degraded, Degraded{t, err})
+		return nil
+	}
+	return err
+}
+
+// observe records the time the provider of t took to run.
+func (w *warmup) observe(t reflect.Type, d time.Duration) {
+	w.mu.Lock()
+	w.times[t] += d
+	w.mu.Unlock()
+}
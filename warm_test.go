@@ -0,0 +1,116 @@
+package inject_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/codegangsta/inject"
+)
+
+type ctxKey struct{}
+
+func Test_InjectorWarm(t *testing.T) {
+	built := 0
+	injector := inject.New()
+	injector.Provide(func(ctx context.Context) (string, error) {
+		built++
+		return ctx.Value(ctxKey{}).(string), nil
+	})
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "warm")
+	expect(t, injector.(inject.Warmer).Warm(ctx), nil)
+	expect(t, built, 1)
+	expect(t, len(injector.Unused()), 1)
+
+	_, err := injector.Invoke(func(s string) { expect(t, s, "warm") })
+	expect(t, err, nil)
+	expect(t, built, 1)
+}
+
+func Test_InjectorWarmCancelled(t *testing.T) {
+	injector := inject.New()
+	injector.Provide(func() int { return 1 })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := injector.(inject.Warmer).Warm(ctx)
+	expect(t, errors.Is(err, context.Canceled), true)
+	var ae *inject.AbortedError
+	expect(t, errors.As(err, &ae), true)
+}
+
+func Test_InjectorProviderContext(t *testing.T) {
+	parent := inject.New()
+	parent.Provide(func(ctx context.Context) string {
+		v, _ := ctx.Value(ctxKey{}).(string)
+		return v
+	})
+	injector := inject.New()
+	injector.SetParent(parent)
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	_, err := injector.InvokeWithContext(ctx, func(s string) { expect(t, s, "request") })
+	expect(t, err, nil)
+
+	other := inject.New()
+	other.Provide(func(ctx context.Context) int { return 1 })
+	expect(t, other.Validate(), nil)
+	_, err = other.Invoke(func(int) {})
+	expect(t, err, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	other.Provide(func() float64 { return 1 })
+	_, err = other.InvokeWithContext(ctx, func(float64) {})
+	expect(t, errors.Is(err, context.Canceled), true)
+}
+
+func Test_InjectorWarmParallel(t *testing.T) {
+	injector := inject.New()
+	injector.Provide(func() int8 { time.Sleep(20 * time.Millisecond); return 1 })
+	injector.Provide(func() int16 { time.Sleep(20 * time.Millisecond); return 2 })
+	injector.Provide(func(int8, int16) int32 { return 3 })
+
+	var events []inject.WarmEvent
+	start := time.Now()
+	err := injector.(inject.Warmer).Warm(context.Background(), inject.WarmParallel(3), inject.WarmProgress(func(e inject.WarmEvent) {
+		events = append(events, e)
+	}))
+	expect(t, err, nil)
+	expect(t, time.Since(start) < 40*time.Millisecond, true)
+
+	expect(t, len(events), 3)
+	for n, e := range events {
+		expect(t, e.Done, n+1)
+		expect(t, e.Total, 3)
+		expect(t, e.Err, nil)
+		if e.Type == reflect.TypeOf(int8(0)) {
+			expect(t, e.Duration >= 20*time.Millisecond, true)
+		}
+		if e.Type == reflect.TypeOf(int32(0)) {
+			expect(t, e.Duration < 20*time.Millisecond, true)
+		}
+	}
+}
+
+func Test_InjectorWarmPartial(t *testing.T) {
+	injector := inject.New()
+	injector.Provide(func() (int, error) { return 0, errors.New("cache down") }, inject.Optional())
+	injector.Provide(func() string { return "db" })
+
+	err := injector.(inject.Warmer).Warm(context.Background())
+	refute(t, err, nil)
+
+	var degraded []inject.Degraded
+	expect(t, injector.(inject.Warmer).Warm(context.Background(), inject.WarmPartial(&degraded)), nil)
+	expect(t, len(degraded), 1)
+	expect(t, degraded[0].Type, reflect.TypeOf(0))
+
+	injector.Provide(func(int) float64 { return 1 })
+	degraded = nil
+	err = injector.(inject.Warmer).Warm(context.Background(), inject.WarmPartial(&degraded))
+	refute(t, err, nil)
+}
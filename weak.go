@@ -0,0 +1,48 @@
+package inject
+
+import "reflect"
+
+// Weak returns a BindOption memoizing the value built by the provider of
+// the binding without keeping it alive: the injector only holds a weak
+// pointer to it, so that a large cache-like value is collected once its
+// consumers dropped it. The provider builds the value again on the next
+// resolution after it was collected. Weak values are neither tracked by
+// Dispose nor passed to OnDestroy hooks, which would keep them alive.
+// Weak has no effect on mapped values, on transient and Shared providers
+// and on types that are not pointers. Weak pointers came with Go 1.24:
+// built with an older Go, Weak values are memoized like the others.
+func Weak() BindOption {
+	return func(e *binding) {
+		e.weak = true
+	}
+}
+
+// weakly reports whether the value of e, provided for t, is memoized with
+// a weak pointer.
+func weakly(t reflect.Type, e *binding) bool {
+	return e.weak && !e.shared && t.Kind() == reflect.Ptr
+}
+
+// provideWeak returns the value of the Weak binding e for t, building it
+// again when it has been collected, as part of c. The provider lock is
+// held.
+func (i *injector) provideWeak(t reflect.Type, e *binding, c *call) (reflect.Value, error) {
+	p := e.provider
+	if ptr := p.weak.value(); ptr != nil {
+		return reflect.NewAt(t.Elem(), ptr), nil
+	}
+	if err := c.done(t); err != nil {
+		return reflect.Value{}, err
+	}
+	val, fresh, err := i.build(t, e, c.at(i))
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if fresh {
+		if err := created(t, e, val); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	p.weak = makeWeak(val.UnsafePointer())
+	return val, nil
+}
@@ -0,0 +1,38 @@
+//go:build go1.24
+
+package inject_test
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/codegangsta/inject"
+)
+
+type bigCache struct {
+	data [1 << 16]byte
+}
+
+func Test_InjectorWeak(t *testing.T) {
+	injector := inject.New()
+	built := 0
+	injector.Provide(func() *bigCache {
+		built++
+		return &bigCache{}
+	}, inject.Weak())
+	cacheType := reflect.TypeOf(&bigCache{})
+
+	held := injector.Get(cacheType).Interface().(*bigCache)
+	held.data[0] = 1
+	runtime.GC()
+	expect(t, injector.Get(cacheType).Interface().(*bigCache), held)
+	expect(t, built, 1)
+	runtime.KeepAlive(held)
+
+	runtime.GC()
+	runtime.GC()
+	c := injector.Get(cacheType).Interface().(*bigCache)
+	expect(t, built, 2)
+	expect(t, c.data[0], byte(0))
+}
@@ -0,0 +1,26 @@
+//go:build go1.24
+
+package inject
+
+import (
+	"unsafe"
+	"weak"
+)
+
+// weakPointer points to the value of a Weak binding without keeping it
+// alive.
+type weakPointer struct {
+	// The type of the weak pointer does not matter, Value returns the
+	// pointer it was made from.
+	p weak.Pointer[byte]
+}
+
+func makeWeak(ptr unsafe.Pointer) weakPointer {
+	return weakPointer{weak.Make((*byte)(ptr))}
+}
+
+// value returns the pointer w was made from, nil if the value it points
+// to was collected.
+func (w weakPointer) value() unsafe.Pointer {
+	return unsafe.Pointer(w.p.Value())
+}
@@ -0,0 +1,20 @@
+//go:build !go1.24
+
+package inject
+
+import "unsafe"
+
+// weakPointer points to the value of a Weak binding. Go releases before
+// 1.24 have no weak pointers: the value is kept alive like a memoized one.
+type weakPointer struct {
+	p unsafe.Pointer
+}
+
+func makeWeak(ptr unsafe.Pointer) weakPointer {
+	return weakPointer{ptr}
+}
+
+// value returns the pointer w was made from.
+func (w weakPointer) value() unsafe.Pointer {
+	return w.p
+}